@@ -0,0 +1,19 @@
+package audit
+
+import (
+	"path/filepath"
+
+	"github.com/Blackjack200/GracticeEssential/util"
+)
+
+var _log = NewLog(filepath.Join(util.WorkingPath, "audit.jsonl"))
+
+func Global() *Log {
+	return _log
+}
+
+// SetGlobal replaces the Log returned by Global, for example with a Log stored in another directory. It must
+// be called before server.SetupFunc.
+func SetGlobal(l *Log) {
+	_log = l
+}
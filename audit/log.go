@@ -0,0 +1,270 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ResultSuccess is the Result of a command that was run without errors.
+	ResultSuccess = "success"
+	// ResultFailure is the Result of a command that was run, but reported an error.
+	ResultFailure = "failure"
+	// ResultDenied is the Result of a command that the source was not allowed to run.
+	ResultDenied = "denied"
+)
+
+// Record is a record of the execution of an administrative command.
+type Record struct {
+	// Time is the time the command was run at.
+	Time time.Time `json:"time"`
+	// Source is the name of the source that ran the command, such as the name of a player or CONSOLE.
+	Source string `json:"source"`
+	// Kind is the kind of the source, such as player or console.
+	Kind string `json:"kind"`
+	// Command is the name of the command run, followed by its sub commands, such as whitelist add.
+	Command string `json:"command"`
+	// Target is the player, IP address or group the command was run on. It is empty if the command has no
+	// target.
+	Target string `json:"target,omitempty"`
+	// Args are the other arguments passed to the command.
+	Args []string `json:"args,omitempty"`
+	// Result is either ResultSuccess, ResultFailure or ResultDenied.
+	Result string `json:"result"`
+	// Error is the error the command reported if its Result is ResultFailure.
+	Error string `json:"error,omitempty"`
+}
+
+// Involves checks if the player with the name passed ran the command of the Record or was its target.
+func (r Record) Involves(name string) bool {
+	return strings.EqualFold(r.Source, name) || strings.EqualFold(r.Target, name)
+}
+
+// String ...
+func (r Record) String() string {
+	s := fmt.Sprintf("%v %v: /%v", r.Time.Format(time.DateTime), r.Source, r.Command)
+	for _, a := range append([]string{r.Target}, r.Args...) {
+		if a != "" {
+			s += " " + a
+		}
+	}
+	s += " (" + r.Result
+	if r.Error != "" {
+		s += ": " + r.Error
+	}
+	return s + ")"
+}
+
+// MaxSize is the size in bytes a Log file may grow to before it is rotated.
+var MaxSize int64 = 8 << 20
+
+// Rotations is the number of rotated Log files that are kept, named after the file with a .1 suffix for the
+// most recent one, .2 for the one before it, and so on.
+var Rotations = 5
+
+// Log is an append-only log of Records, stored as JSON, one Record per line, in a file. When the file grows
+// larger than MaxSize, it is rotated.
+type Log struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewLog returns a Log stored in the file at the path passed. The file is created when the first Record is
+// appended to it.
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// rotatedName returns the name of the nth most recent rotated file of the Log, starting at 1, or the name of
+// the current file if n is 0.
+func (l *Log) rotatedName(n int) string {
+	if n == 0 {
+		return l.path
+	}
+	return fmt.Sprintf("%v.%v", l.path, n)
+}
+
+// Append appends the Record passed to the Log. If its Time is zero, it is set to the current time.
+func (l *Log) Append(r Record) error {
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if info, err := os.Stat(l.path); err == nil && info.Size() > 0 && info.Size()+int64(len(data)) > MaxSize {
+		if err := l.rotateNoLock(); err != nil {
+			return fmt.Errorf("rotate audit log: %w", err)
+		}
+	}
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// rotateNoLock renames the files of the Log to the name of the next older rotated file, removing the oldest.
+func (l *Log) rotateNoLock() error {
+	if err := os.Remove(l.rotatedName(Rotations)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for n := Rotations - 1; n >= 0; n-- {
+		if err := os.Rename(l.rotatedName(n), l.rotatedName(n+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Records returns the Records in the Log, including those in the rotated files, for which the function passed
+// returns true, newest first. f may be nil to return all Records. Lines that are not valid Records are
+// skipped.
+func (l *Log) Records(f func(Record) bool) ([]Record, error) {
+	var records []Record
+	err := l.walk(f, func(r Record) bool {
+		records = append(records, r)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Page returns the page with the number passed, starting at 1, of the Records for which f returns true,
+// newest first, with size Records per page. more is true if there are more Records after the page. Reading
+// stops once the page is filled, so only the files holding the page and the pages before it are read.
+func (l *Log) Page(f func(Record) bool, page, size int) (records []Record, more bool, err error) {
+	if page < 1 || size < 1 {
+		return nil, false, nil
+	}
+	skip := (page - 1) * size
+	err = l.walk(f, func(r Record) bool {
+		switch {
+		case skip > 0:
+			skip--
+		case len(records) == size:
+			more = true
+			return false
+		default:
+			records = append(records, r)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return records, more, nil
+}
+
+// walk calls yield with the Records in the Log for which f returns true, newest first, until yield returns
+// false. The files of the Log are opened before reading them, so that the Log is only locked while opening
+// them, and Records appended while walking the Log are not read.
+func (l *Log) walk(f func(Record) bool, yield func(Record) bool) error {
+	files, sizes, err := l.open()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, file := range files {
+			_ = file.Close()
+		}
+	}()
+	for i, file := range files {
+		stop := false
+		err := readBackwards(file, sizes[i], func(line []byte) bool {
+			var r Record
+			if json.Unmarshal(line, &r) != nil || (f != nil && !f(r)) {
+				return true
+			}
+			stop = !yield(r)
+			return !stop
+		})
+		if err != nil {
+			return err
+		}
+		if stop {
+			break
+		}
+	}
+	return nil
+}
+
+// open opens the files of the Log that exist, newest first, and returns them together with their sizes.
+func (l *Log) open() ([]*os.File, []int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var (
+		files []*os.File
+		sizes []int64
+	)
+	for n := 0; n <= Rotations; n++ {
+		file, err := os.Open(l.rotatedName(n))
+		if os.IsNotExist(err) {
+			continue
+		}
+		var info os.FileInfo
+		if err == nil {
+			if info, err = file.Stat(); err != nil {
+				_ = file.Close()
+			}
+		}
+		if err != nil {
+			for _, file := range files {
+				_ = file.Close()
+			}
+			return nil, nil, err
+		}
+		files, sizes = append(files, file), append(sizes, info.Size())
+	}
+	return files, sizes, nil
+}
+
+// readChunkSize is the number of bytes read from a file of a Log at a time by readBackwards.
+const readChunkSize = 64 << 10
+
+// readBackwards calls yield with the non-empty lines in the first size bytes of the file passed, last line
+// first, until yield returns false. The slice passed to yield is only valid until it returns.
+func readBackwards(file *os.File, size int64, yield func(line []byte) bool) error {
+	var rest []byte
+	for pos := size; pos > 0; {
+		n := min(readChunkSize, pos)
+		pos -= n
+		b := make([]byte, int(n)+len(rest))
+		if _, err := file.ReadAt(b[:n], pos); err != nil {
+			return err
+		}
+		copy(b[n:], rest)
+		for {
+			i := bytes.LastIndexByte(b, '\n')
+			if i < 0 {
+				break
+			}
+			if line := b[i+1:]; len(line) != 0 && !yield(line) {
+				return nil
+			}
+			b = b[:i]
+		}
+		// The part of the chunk before the first newline is the end of a line that started in the chunk
+		// before it.
+		rest = b
+	}
+	if len(rest) != 0 {
+		yield(rest)
+	}
+	return nil
+}
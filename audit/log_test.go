@@ -0,0 +1,116 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// appendRecords appends n Records to the Log passed, with the Command of the ith Record set to cmd<i>.
+func appendRecords(t *testing.T, l *Log, n int) {
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		r := Record{Time: start.Add(time.Duration(i) * time.Second), Source: "Steve", Command: fmt.Sprint("cmd", i)}
+		if i%3 == 0 {
+			r.Target = "Alex"
+		}
+		if err := l.Append(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLogPage(t *testing.T) {
+	defer func(size int64, rotations int) {
+		MaxSize, Rotations = size, rotations
+	}(MaxSize, Rotations)
+	MaxSize, Rotations = 1024, 3
+
+	l := NewLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	appendRecords(t, l, 50)
+	if _, err := os.Stat(l.rotatedName(3)); err != nil {
+		t.Fatalf("expected the log to be rotated: %v", err)
+	}
+
+	all, err := l.Records(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) == 0 || all[0].Command != "cmd49" {
+		t.Fatalf("expected the newest record first, got %v", all)
+	}
+	for i := 1; i < len(all); i++ {
+		if !all[i].Time.Before(all[i-1].Time) {
+			t.Fatalf("expected records newest first, got %v before %v", all[i-1], all[i])
+		}
+	}
+
+	for page := 1; ; page++ {
+		records, more, err := l.Page(nil, page, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := (page - 1) * 7
+		want := all[start:min(start+7, len(all))]
+		if len(records) != len(want) {
+			t.Fatalf("expected %v records on page %v, got %v", len(want), page, len(records))
+		}
+		for i := range records {
+			if records[i].Command != want[i].Command {
+				t.Fatalf("expected %v on page %v, got %v", want[i], page, records[i])
+			}
+		}
+		if more != (start+7 < len(all)) {
+			t.Fatalf("expected more to be %v on page %v", !more, page)
+		}
+		if !more {
+			break
+		}
+	}
+	if records, more, err := l.Page(nil, 100, 7); err != nil || len(records) != 0 || more {
+		t.Fatalf("expected no records on a page that does not exist, got %v, %v, %v", records, more, err)
+	}
+}
+
+func TestLogPageFilter(t *testing.T) {
+	l := NewLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	appendRecords(t, l, 10)
+	records, more, err := l.Page(func(r Record) bool {
+		return r.Involves("alex")
+	}, 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !more || len(records) != 3 || records[0].Command != "cmd9" || records[2].Command != "cmd3" {
+		t.Fatalf("expected cmd9, cmd6 and cmd3 followed by more records, got %v, %v", records, more)
+	}
+}
+
+func TestLogLongLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l := NewLog(path)
+	long := strings.Repeat("x", readChunkSize+100)
+	if err := l.Append(Record{Command: "first", Args: []string{long}}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("not a record\n\n")
+	_ = f.Close()
+	if err := l.Append(Record{Command: "second", Args: []string{long + long}}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := l.Records(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Command != "second" || records[1].Command != "first" || records[1].Args[0] != long {
+		t.Fatalf("expected the second and first record, got %v records", len(records))
+	}
+}
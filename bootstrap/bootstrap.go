@@ -21,7 +21,10 @@ func signalHandler(log *slog.Logger, callback func()) {
 	go func(fn func()) {
 		<-c
 		if err := server.Global().Close(); err != nil {
-			log.Error("error shutting down server: %v", err)
+			log.Error("error shutting down server", "err", err)
+		}
+		if err := server.Close(); err != nil {
+			log.Error("error closing permission lists", "err", err)
 		}
 		if fn != nil {
 			fn()
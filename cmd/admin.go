@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Blackjack200/GracticeEssential/convert"
+	"github.com/Blackjack200/GracticeEssential/eform"
+	"github.com/Blackjack200/GracticeEssential/permission"
+	"github.com/Blackjack200/GracticeEssential/server"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Admin opens a panel of forms to run the administrative commands without typing them.
+type Admin struct{}
+
+func (Admin) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	p, ok := src.(*player.Player)
+	if !ok {
+		o.Error("This command must use in game")
+		return
+	}
+	eform.StackOf(eform.Player{Player: p}).ReturnOnClose().Push(adminPanel())
+}
+
+func (Admin) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.admin")
+}
+
+// runAs runs the command passed as the player that submitted a form of the admin panel and sends the output
+// to the player. The command is only run if the player is allowed to run it.
+func runAs(s eform.Submitter, c cmd.Runnable) {
+	p, ok := eform.PlayerOf(s)
+	if !ok {
+		return
+	}
+	o := &cmd.Output{}
+	if a, ok := c.(cmd.Allower); ok && !a.Allow(p) {
+		o.Error("You are not allowed to do this")
+	} else {
+		c.Run(p, o, p.Tx())
+	}
+	p.SendCommandOutput(o)
+}
+
+// back returns the submitter n forms back on its navigation stack.
+func back(s eform.Submitter, n int) {
+	eform.StackOf(s).PopN(n)
+}
+
+func adminPanel() eform.Menu {
+	return eform.NewMenu("Admin panel").
+		WithButton(eform.NewButton("Players", ""), eform.Push(playersMenu())).
+		WithButton(eform.NewButton("Banned players", ""), eform.Push(banListMenu())).
+		WithButton(eform.NewButton("Ban player", ""), eform.Push(banForm("", 1))).
+		WithButton(eform.NewButton("Game mode", ""), eform.Push(gameModeForm())).
+		WithButton(eform.NewButton("Difficulty", ""), eform.Push(difficultyForm())).
+		WithButton(eform.NewButton("Default game mode", ""), eform.Push(defaultGameModeForm())).
+		WithButton(eform.NewButton("Set world spawn", ""), eform.Push(setWorldSpawnModal()))
+}
+
+func playersMenu() eform.Dynamic[string] {
+	return eform.NewDynamic("Players", func(s eform.Submitter) []string {
+		var tx *world.Tx
+		if p, ok := eform.PlayerOf(s); ok {
+			tx = p.Tx()
+		}
+		return server.PlayerNames(tx)
+	}, func(name string) eform.Button {
+		return eform.NewButton(name, "")
+	}, func(s eform.Submitter, name string) {
+		eform.StackOf(s).Push(playerMenu(name))
+	}).WithBody("Select a player.").OnBack(eform.Back)
+}
+
+func playerMenu(name string) eform.Menu {
+	return eform.NewMenu(name).
+		WithButton(eform.NewButton("Kick", ""), eform.Push(kickForm(name))).
+		WithButton(eform.NewButton("Ban", ""), eform.Push(banForm(name, 2))).
+		WithButton(eform.NewButton("Op", ""), func(s eform.Submitter) {
+			runAs(s, Op{Target: name})
+			back(s, 1)
+		}).
+		WithButton(eform.NewButton("De-op", ""), func(s eform.Submitter) {
+			runAs(s, DeOp{Target: name})
+			back(s, 1)
+		}).
+		WithButton(eform.NewButton("Back", ""), eform.Back)
+}
+
+func kickForm(name string) eform.Custom {
+	f, reason := eform.Add(eform.NewCustom("Kick "+name), eform.NewInput("Reason", "", "Optional"))
+	return f.OnResponse(func(s eform.Submitter, r eform.Response) {
+		p, ok := eform.PlayerOf(s)
+		if !ok {
+			return
+		}
+		h, ok := server.Global().PlayerByName(name)
+		if !ok {
+			p.Message(fmt.Sprintf("§cPlayer %v is not online", name))
+			back(s, 2)
+			return
+		}
+		t, ok := h.Entity(p.Tx())
+		if !ok {
+			p.Message(fmt.Sprintf("§cPlayer %v is not in your world", name))
+			back(s, 2)
+			return
+		}
+		runAs(s, Kick{Target: []cmd.Target{t}, Reason: reason.Value(r)})
+		back(s, 2)
+	})
+}
+
+// banForm returns the form to ban the player with the name passed, which may be empty to have it filled out.
+// After the player was banned, the submitter is sent n forms back.
+func banForm(name string, n int) eform.Custom {
+	f, target := eform.Add(eform.NewCustom("Ban player"), eform.NewInput("Player", name, "Name").
+		WithValidator(eform.ValidateLength(1, 64)))
+	f, duration := eform.Add(f, eform.NewInput("Duration", "", "Permanent if empty, such as 30m, 12h or 7d").
+		WithValidator(func(s string) error {
+			if s == "" {
+				return nil
+			}
+			_, err := convert.ParseDuration(s)
+			return err
+		}))
+	f, reason := eform.Add(f, eform.NewInput("Reason", "", "Optional"))
+	return f.OnResponse(func(s eform.Submitter, r eform.Response) {
+		if d := duration.Value(r); d != "" {
+			runAs(s, TempBan{Target: target.Value(r), Duration: d, Reason: cmd.Varargs(reason.Value(r))})
+		} else {
+			runAs(s, Ban{Target: target.Value(r), Reason: cmd.Varargs(reason.Value(r))})
+		}
+		back(s, n)
+	})
+}
+
+func banListMenu() eform.Dynamic[string] {
+	return eform.NewDynamic("Banned players", func(eform.Submitter) []string {
+		names := permission.BanEntry().GetAll()
+		sort.Strings(names)
+		return names
+	}, func(name string) eform.Button {
+		return eform.NewButton(name, "")
+	}, func(s eform.Submitter, name string) {
+		body := fmt.Sprintf("Do you want to unban %v?", name)
+		if rec, ok := permission.BanEntry().Record(name); ok {
+			body = fmt.Sprintf("Banned by %v at %v.\n%v\n\n%v", rec.Source, rec.Created.Format(time.DateTime), rec.Message(time.Now()), body)
+		}
+		eform.StackOf(s).Push(eform.NewModal("Unban "+name).
+			WithBody(body).
+			WithButton1(eform.YesButton(), func(s eform.Submitter) {
+				runAs(s, Unban{Target: name})
+				back(s, 1)
+			}).
+			WithButton2(eform.NoButton(), eform.Back))
+	}).WithBody("Select a player to unban.").OnBack(eform.Back)
+}
+
+func gameModeForm() eform.Custom {
+	f, mode := eform.Add(eform.NewCustom("Game mode"), eform.NewDropdown("Game mode", convert.GameModes, 0))
+	return f.OnResponse(func(s eform.Submitter, r eform.Response) {
+		runAs(s, GameMode{GameMode: mode.Value(r)})
+		back(s, 1)
+	})
+}
+
+func difficultyForm() eform.Custom {
+	f, diff := eform.Add(eform.NewCustom("Difficulty"), eform.NewDropdown("Difficulty", convert.Difficulties, 2))
+	return f.OnResponse(func(s eform.Submitter, r eform.Response) {
+		runAs(s, Difficulty{Diff: diff.Value(r)})
+		back(s, 1)
+	})
+}
+
+func defaultGameModeForm() eform.Custom {
+	f, mode := eform.Add(eform.NewCustom("Default game mode"), eform.NewDropdown("Default game mode", convert.GameModes, 0))
+	return f.OnResponse(func(s eform.Submitter, r eform.Response) {
+		runAs(s, DefaultGameMode{GameMode: mode.Value(r)})
+		back(s, 1)
+	})
+}
+
+func setWorldSpawnModal() eform.Modal {
+	return eform.NewModal("Set world spawn").
+		WithBody("Do you want to set the world spawn to your position?").
+		WithButton1(eform.YesButton(), func(s eform.Submitter) {
+			runAs(s, SetWorldSpawn{})
+			back(s, 1)
+		}).
+		WithButton2(eform.NoButton(), eform.Back)
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Blackjack200/GracticeEssential/audit"
+	"github.com/Blackjack200/GracticeEssential/permission"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// auditPageSize is the number of records shown per page of /audit.
+const auditPageSize = 10
+
+// record records the execution of the command passed by the source passed in the audit log, with the result
+// taken from the errors in the output passed. It is deferred at the start of the Run method of every command
+// that changes the state of the server, so that the output holds the result once it is called.
+func record(src cmd.Source, o *cmd.Output, command, target string, args ...string) {
+	r := audit.Record{
+		Source:  sourceName(src),
+		Kind:    permission.KindOf(src).String(),
+		Command: command,
+		Target:  target,
+		Result:  audit.ResultSuccess,
+	}
+	for _, a := range args {
+		if a != "" {
+			r.Args = append(r.Args, a)
+		}
+	}
+	if errs := o.Errors(); len(errs) != 0 {
+		r.Result = audit.ResultFailure
+		msgs := make([]string, len(errs))
+		for i, err := range errs {
+			msgs[i] = err.Error()
+		}
+		r.Error = strings.Join(msgs, "; ")
+	}
+	if err := audit.Global().Append(r); err != nil {
+		o.Errorf("Could not write to the audit log: %v", err)
+	}
+}
+
+// auditHandler records the commands players try to run without being allowed to run any of their overloads in
+// the audit log. The commands that are run are recorded by the commands themselves.
+type auditHandler struct{}
+
+func (auditHandler) HandleCommandExecution(ctx *event.Context[*player.Player], command cmd.Command, args []string) {
+	p := ctx.Val()
+	if ctx.Cancelled() || len(command.Runnables(p)) != 0 {
+		return
+	}
+	r := audit.Record{
+		Source:  p.Name(),
+		Kind:    permission.SourcePlayer.String(),
+		Command: command.Name(),
+		Args:    args,
+		Result:  audit.ResultDenied,
+	}
+	_ = audit.Global().Append(r)
+}
+
+type Audit struct {
+	Page cmd.Optional[int] `cmd:"page"`
+}
+
+func (a Audit) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	printAudit(o, "", a.Page.LoadOr(1))
+}
+
+func (Audit) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.audit")
+}
+
+type AuditPlayer struct {
+	Player string            `cmd:"player"`
+	Page   cmd.Optional[int] `cmd:"page"`
+}
+
+func (a AuditPlayer) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	printAudit(o, a.Player, a.Page.LoadOr(1))
+}
+
+func (AuditPlayer) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.audit")
+}
+
+// printAudit prints the page passed of the audit log to the output passed, newest first. If name is not empty,
+// only the records of commands the player with the name ran or was the target of are printed.
+func printAudit(o *cmd.Output, name string, page int) {
+	var f func(audit.Record) bool
+	if name != "" {
+		f = func(r audit.Record) bool {
+			return r.Involves(name)
+		}
+	}
+	records, more, err := audit.Global().Page(f, page, auditPageSize)
+	if err != nil {
+		o.Errorf("Could not read the audit log: %v", err)
+		return
+	}
+	if len(records) == 0 {
+		if page == 1 {
+			o.Print("There are no audit records")
+		} else {
+			o.Errorf("Page %v does not exist", page)
+		}
+		return
+	}
+	o.Printf("Audit log page %v:", page)
+	for _, r := range records {
+		o.Print(r.String())
+	}
+	if more {
+		next := strings.TrimSpace("/audit " + name)
+		o.Printf("Run %v %v for the next page", next, page+1)
+	}
+}
+
+// targetNames returns the names of the targets passed, separated by commas.
+func targetNames(targets []cmd.Target) string {
+	names := make([]string, 0, len(targets))
+	for _, t := range targets {
+		if n, ok := t.(cmd.NamedTarget); ok {
+			names = append(names, n.Name())
+		} else {
+			names = append(names, fmt.Sprint(t))
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -1,34 +1,204 @@
 package cmd
 
 import (
+	"github.com/df-mc/dragonfly/server/player"
 	"github.com/df-mc/dragonfly/server/world"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/Blackjack200/GracticeEssential/convert"
 	"github.com/Blackjack200/GracticeEssential/permission"
 	"github.com/Blackjack200/GracticeEssential/server"
 	"github.com/df-mc/dragonfly/server/cmd"
 )
 
 type Ban struct {
-	Target string
+	Target   string
+	Duration string      `optional:""`
+	Reason   cmd.Varargs `optional:""`
 }
 
 func (b Ban) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "ban", b.Target, b.Duration, string(b.Reason))
 	defer o.Messages()
 	if b.Target == "" {
 		o.Error("Command argument error")
 		return
 	}
-	if t, found := server.Global().PlayerByName(b.Target); found {
-		t.Close()
+	reason := string(b.Reason)
+	var expires *time.Time
+	if b.Duration != "" {
+		if d, err := convert.ParseDuration(b.Duration); err == nil {
+			t := time.Now().Add(d)
+			expires = &t
+		} else {
+			// The duration is optional, so it is the first word of the reason if it is not a duration.
+			reason = strings.TrimSpace(b.Duration + " " + reason)
+		}
+	}
+	ban(src, o, tx, permission.BanRecord{Name: b.Target, Reason: reason, Source: sourceName(src), Expires: expires})
+}
+
+// ban adds the BanRecord passed to the ban list and disconnects the player banned if it is online. If the name
+// of the record is an XUID, the XUID is banned instead.
+func ban(src cmd.Source, o *cmd.Output, tx *world.Tx, r permission.BanRecord) {
+	r.Created = time.Now()
+	target := r.Name
+	if isXUID(r.Name) {
+		r.Name, r.XUID = "", r.Name
+	} else if id, ok := permission.LastIdentity(r.Name); ok {
+		// Ban the XUID of the player right away, so that it cannot evade the ban by changing its name.
+		r.XUID = id.XUID
+	}
+	if err := permission.BanEntry().Ban(r); err != nil {
+		o.Errorf("Could not ban player %v: %v", target, err)
+		return
+	}
+	t, found := server.Global().PlayerByName(r.Name)
+	if r.Name == "" {
+		t, found = server.Global().PlayerByXUID(r.XUID)
+	}
+	if found {
+		disconnect(tx, t, r.Message(r.Created))
+	}
+	if r.Expires != nil {
+		o.Printf("Banned player %v for %v", target, convert.DumpDuration(r.Expires.Sub(r.Created)))
+		return
+	}
+	o.Printf("Banned player %v", target)
+}
+
+// isXUID checks if the target passed is an XUID rather than the name of a player. Gamertags always start with
+// a letter, so a target made up of digits only cannot be a name.
+func isXUID(target string) bool {
+	if target == "" {
+		return false
+	}
+	for _, c := range target {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// sourceName returns the name of the command source passed, such as the name of a player or CONSOLE.
+func sourceName(src cmd.Source) string {
+	if t, ok := src.(cmd.NamedTarget); ok {
+		return t.Name()
 	}
-	permission.BanEntry().Add(b.Target)
-	o.Printf("Banned player %v", b.Target)
+	return "unknown"
 }
 
 func (b Ban) Allow(s cmd.Source) bool {
-	return AllowImpl(s)
+	return AllowImpl(s, "essential.command.ban")
+}
+
+type TempBan struct {
+	Target   string
+	Duration string
+	Reason   cmd.Varargs `optional:""`
+}
+
+func (b TempBan) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "tempban", b.Target, b.Duration, string(b.Reason))
+	if b.Target == "" {
+		o.Error("Command argument error")
+		return
+	}
+	d, err := convert.ParseDuration(b.Duration)
+	if err != nil {
+		o.Error(err)
+		return
+	}
+	expires := time.Now().Add(d)
+	ban(src, o, tx, permission.BanRecord{Name: b.Target, Reason: string(b.Reason), Source: sourceName(src), Expires: &expires})
+}
+
+func (b TempBan) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.tempban")
+}
+
+// disconnect disconnects the player of the handle passed with the message passed. If the player is in another
+// world than that of the transaction passed, it is disconnected asynchronously.
+func disconnect(tx *world.Tx, h *world.EntityHandle, msg string) {
+	if tx != nil {
+		if e, ok := h.Entity(tx); ok {
+			if p, ok := e.(*player.Player); ok {
+				p.Disconnect(msg)
+			}
+			return
+		}
+	}
+	go h.ExecWorld(func(_ *world.Tx, e world.Entity) {
+		if p, ok := e.(*player.Player); ok {
+			p.Disconnect(msg)
+		}
+	})
+}
+
+type BanIP struct {
+	Target string
+	Reason cmd.Varargs `optional:""`
+}
+
+func (b BanIP) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "ban-ip", b.Target, string(b.Reason))
+	if b.Target == "" {
+		o.Error("Command argument error")
+		return
+	}
+	address, err := permission.ParseAddress(b.Target)
+	if err != nil {
+		id, ok := permission.LastIdentity(b.Target)
+		if !ok || id.IP == nil {
+			o.Errorf("%v is neither a valid IP address nor a player that joined", b.Target)
+			return
+		}
+		address = id.IP.String()
+	}
+	r := permission.BanRecord{IP: address, Reason: string(b.Reason), Source: sourceName(src), Created: time.Now()}
+	if err := permission.BanEntry().Ban(r); err != nil {
+		o.Errorf("Could not ban IP address %v: %v", address, err)
+		return
+	}
+	msg := r.Message(r.Created)
+	for p := range server.Global().Players(tx) {
+		if r.Matches("", "", permission.IPOf(p.Addr())) {
+			p.Disconnect(msg)
+		}
+	}
+	o.Printf("Banned IP address %v", address)
+}
+
+func (b BanIP) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.ban-ip")
+}
+
+type PardonIP struct {
+	Target string
+}
+
+func (u PardonIP) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "pardon-ip", u.Target)
+	address, err := permission.ParseAddress(u.Target)
+	if err != nil {
+		o.Error(err)
+		return
+	}
+	if ok, err := permission.BanEntry().UnbanAddress(address); err != nil {
+		o.Errorf("Could not unban IP address %v: %v", address, err)
+		return
+	} else if !ok {
+		o.Errorf("IP address %v is not banned", address)
+		return
+	}
+	o.Printf("Unbanned IP address %v", address)
+}
+
+func (u PardonIP) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.pardon-ip")
 }
 
 type Unban struct {
@@ -36,28 +206,43 @@ type Unban struct {
 }
 
 func (u Unban) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "unban", u.Target)
 	if u.Target == "" {
 		o.Error("Command argument error")
 		return
 	}
-	permission.BanEntry().Delete(u.Target)
+	unban := permission.BanEntry().Unban
+	if isXUID(u.Target) {
+		unban = permission.BanEntry().UnbanXUID
+	}
+	if ok, err := unban(u.Target); err != nil {
+		o.Errorf("Could not unban player %v: %v", u.Target, err)
+		return
+	} else if !ok {
+		o.Errorf("Player %v is not banned", u.Target)
+		return
+	}
 	o.Printf("Unbanned player %v", u.Target)
 }
 
 func (u Unban) Allow(s cmd.Source) bool {
-	return AllowImpl(s)
+	return AllowImpl(s, "essential.command.unban")
 }
 
 type BanList struct {
 }
 
 func (BanList) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
-	arr := permission.BanEntry().GetAll()
-	sort.Strings(arr)
-	o.Printf("There are %v total banned players:", len(arr))
-	o.Print(strings.Join(arr, ", "))
+	records := permission.BanEntry().Records()
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].String() < records[j].String()
+	})
+	o.Printf("There are %v total bans:", len(records))
+	for _, r := range records {
+		o.Print(r.String())
+	}
 }
 
 func (b BanList) Allow(s cmd.Source) bool {
-	return AllowImpl(s)
+	return AllowImpl(s, "essential.command.banlist")
 }
@@ -12,6 +12,7 @@ type DefaultGameMode struct {
 }
 
 func (d DefaultGameMode) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "defaultgamemode", "", d.GameMode)
 	mode, err := convert.ParseGameMode(d.GameMode)
 	if err != nil {
 		o.Error(err)
@@ -22,5 +23,5 @@ func (d DefaultGameMode) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
 }
 
 func (d DefaultGameMode) Allow(s cmd.Source) bool {
-	return AllowImpl(s)
+	return AllowImpl(s, "essential.command.defaultgamemode")
 }
@@ -12,6 +12,7 @@ type Difficulty struct {
 }
 
 func (d Difficulty) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "difficulty", "", d.Diff)
 	if di, err := convert.ParseDifficulty(d.Diff); err != nil {
 		o.Error(err)
 	} else {
@@ -21,5 +22,5 @@ func (d Difficulty) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
 }
 
 func (d Difficulty) Allow(s cmd.Source) bool {
-	return AllowImpl(s)
+	return AllowImpl(s, "essential.command.difficulty")
 }
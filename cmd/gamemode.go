@@ -12,7 +12,8 @@ type GameMode struct {
 }
 
 func (g GameMode) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
-	if AllowImpl(src) {
+	defer record(src, o, "gamemode", "", g.GameMode)
+	if g.Allow(src) {
 		if p, ok := src.(*player.Player); ok {
 			mode, err := convert.ParseGameMode(g.GameMode)
 			if err != nil {
@@ -25,10 +26,10 @@ func (g GameMode) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
 			o.Error("This command must use in game")
 		}
 	} else {
-		o.Error("You are not operator")
+		o.Error("You are not allowed to do this")
 	}
 }
 
 func (g GameMode) Allow(s cmd.Source) bool {
-	return AllowImpl(s)
+	return AllowImpl(s, "essential.command.gamemode")
 }
@@ -9,17 +9,17 @@ import (
 
 type GC struct{}
 
-func (GC) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
-	if AllowImpl(src) {
+func (g GC) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	if g.Allow(src) {
 		a, b := gc()
 		o.Printf("Allocated Memory freed: %v MB", (b.Sys-a.Sys)/1024/1024)
 	} else {
-		o.Error("You are not operator")
+		o.Error("You are not allowed to do this")
 	}
 }
 
 func (GC) Allow(s cmd.Source) bool {
-	return AllowImpl(s)
+	return AllowImpl(s, "essential.command.gc")
 }
 
 func gc() (runtime.MemStats, runtime.MemStats) {
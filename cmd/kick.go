@@ -14,6 +14,7 @@ type Kick struct {
 }
 
 func (b Kick) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "kick", targetNames(b.Target), b.Reason)
 	if b.Target == nil {
 		o.Error("Target not found")
 		return
@@ -35,5 +36,5 @@ func (b Kick) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
 }
 
 func (Kick) Allow(s cmd.Source) bool {
-	return AllowImpl(s)
+	return AllowImpl(s, "essential.command.kick")
 }
@@ -12,21 +12,25 @@ type Op struct {
 }
 
 func (b Op) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "op", b.Target)
 	if b.Target == "" {
 		o.Error("Command argument error")
 		return
 	}
+	if err := permission.OpEntry().Add(b.Target); err != nil {
+		o.Error(err)
+		return
+	}
 	if _, found := server.Global().PlayerByName(b.Target); found {
 		op := &cmd.Output{}
 		op.Print("You have been opped")
 		src.SendCommandOutput(op)
 	}
-	permission.OpEntry().Add(b.Target)
 	o.Printf("Opped: %v", b.Target)
 }
 
 func (Op) Allow(s cmd.Source) bool {
-	return AllowImpl(s)
+	return AllowImpl(s, "essential.command.op")
 }
 
 type DeOp struct {
@@ -34,14 +38,18 @@ type DeOp struct {
 }
 
 func (b DeOp) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "deop", b.Target)
 	if b.Target == "" {
 		o.Error("Command argument error")
 		return
 	}
-	permission.OpEntry().Delete(b.Target)
+	if err := permission.OpEntry().Delete(b.Target); err != nil {
+		o.Error(err)
+		return
+	}
 	o.Printf("De-opped: %v", b.Target)
 }
 
 func (DeOp) Allow(s cmd.Source) bool {
-	return AllowImpl(s)
+	return AllowImpl(s, "essential.command.deop")
 }
@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Blackjack200/GracticeEssential/permission"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+type PermCheck struct {
+	Check  cmd.SubCommand `cmd:"check"`
+	Player string         `cmd:"player"`
+	Node   string         `cmd:"node"`
+}
+
+func (c PermCheck) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	if permission.PermissionEntry().Has(c.Player, c.Node) {
+		o.Printf("Player %v has permission %v", c.Player, c.Node)
+	} else {
+		o.Printf("Player %v does not have permission %v", c.Player, c.Node)
+	}
+}
+
+func (PermCheck) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.perm.check")
+}
+
+type PermGroupList struct {
+	Group cmd.SubCommand `cmd:"group"`
+	List  cmd.SubCommand `cmd:"list"`
+}
+
+func (PermGroupList) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	groups := permission.PermissionEntry().Groups()
+	o.Printf("There are %v groups: %v", len(groups), strings.Join(groups, ", "))
+}
+
+func (PermGroupList) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.perm.group")
+}
+
+type PermGroupInfo struct {
+	Group cmd.SubCommand `cmd:"group"`
+	Info  cmd.SubCommand `cmd:"info"`
+	Name  string         `cmd:"group"`
+}
+
+func (c PermGroupInfo) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	g, ok := permission.PermissionEntry().Group(c.Name)
+	if !ok {
+		o.Errorf("Group %v does not exist", c.Name)
+		return
+	}
+	o.Printf("Group %v inherits from: %v", c.Name, strings.Join(g.Inherits, ", "))
+	printPermissions(o, g.Permissions)
+}
+
+func (PermGroupInfo) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.perm.group")
+}
+
+type PermGroupCreate struct {
+	Group  cmd.SubCommand `cmd:"group"`
+	Create cmd.SubCommand `cmd:"create"`
+	Name   string         `cmd:"group"`
+}
+
+func (c PermGroupCreate) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "perm group create", c.Name)
+	if err := permission.PermissionEntry().CreateGroup(c.Name); err != nil {
+		o.Error(err)
+		return
+	}
+	o.Printf("Created group %v", c.Name)
+}
+
+func (PermGroupCreate) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.perm.group")
+}
+
+type PermGroupDelete struct {
+	Group  cmd.SubCommand `cmd:"group"`
+	Delete cmd.SubCommand `cmd:"delete"`
+	Name   string         `cmd:"group"`
+}
+
+func (c PermGroupDelete) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "perm group delete", c.Name)
+	if err := permission.PermissionEntry().DeleteGroup(c.Name); err != nil {
+		o.Error(err)
+		return
+	}
+	o.Printf("Deleted group %v", c.Name)
+}
+
+func (PermGroupDelete) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.perm.group")
+}
+
+type PermGroupSet struct {
+	Group cmd.SubCommand     `cmd:"group"`
+	Set   cmd.SubCommand     `cmd:"set"`
+	Name  string             `cmd:"group"`
+	Node  string             `cmd:"node"`
+	Value cmd.Optional[bool] `cmd:"value"`
+}
+
+func (c PermGroupSet) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	value := c.Value.LoadOr(true)
+	defer record(src, o, "perm group set", c.Name, c.Node, fmt.Sprint(value))
+	if err := permission.PermissionEntry().SetGroupPermission(c.Name, c.Node, value); err != nil {
+		o.Error(err)
+		return
+	}
+	o.Printf("Set permission %v to %v for group %v", c.Node, value, c.Name)
+}
+
+func (PermGroupSet) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.perm.group")
+}
+
+type PermGroupUnset struct {
+	Group cmd.SubCommand `cmd:"group"`
+	Unset cmd.SubCommand `cmd:"unset"`
+	Name  string         `cmd:"group"`
+	Node  string         `cmd:"node"`
+}
+
+func (c PermGroupUnset) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "perm group unset", c.Name, c.Node)
+	if err := permission.PermissionEntry().UnsetGroupPermission(c.Name, c.Node); err != nil {
+		o.Error(err)
+		return
+	}
+	o.Printf("Unset permission %v for group %v", c.Node, c.Name)
+}
+
+func (PermGroupUnset) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.perm.group")
+}
+
+type PermGroupInherit struct {
+	Group   cmd.SubCommand `cmd:"group"`
+	Inherit cmd.SubCommand `cmd:"inherit"`
+	Name    string         `cmd:"group"`
+	Parent  string         `cmd:"parent"`
+}
+
+func (c PermGroupInherit) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "perm group inherit", c.Name, c.Parent)
+	if err := permission.PermissionEntry().AddParent(c.Name, c.Parent); err != nil {
+		o.Error(err)
+		return
+	}
+	o.Printf("Group %v now inherits from %v", c.Name, c.Parent)
+}
+
+func (PermGroupInherit) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.perm.group")
+}
+
+type PermGroupUninherit struct {
+	Group     cmd.SubCommand `cmd:"group"`
+	Uninherit cmd.SubCommand `cmd:"uninherit"`
+	Name      string         `cmd:"group"`
+	Parent    string         `cmd:"parent"`
+}
+
+func (c PermGroupUninherit) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "perm group uninherit", c.Name, c.Parent)
+	if err := permission.PermissionEntry().RemoveParent(c.Name, c.Parent); err != nil {
+		o.Error(err)
+		return
+	}
+	o.Printf("Group %v no longer inherits from %v", c.Name, c.Parent)
+}
+
+func (PermGroupUninherit) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.perm.group")
+}
+
+type PermPlayerInfo struct {
+	Player cmd.SubCommand `cmd:"player"`
+	Info   cmd.SubCommand `cmd:"info"`
+	Name   string         `cmd:"player"`
+}
+
+func (c PermPlayerInfo) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	o.Printf("Player %v is a member of: %v", c.Name, strings.Join(permission.PermissionEntry().PlayerGroups(c.Name), ", "))
+	printPermissions(o, permission.PermissionEntry().Player(c.Name).Permissions)
+}
+
+func (PermPlayerInfo) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.perm.player")
+}
+
+type PermPlayerSet struct {
+	Player cmd.SubCommand     `cmd:"player"`
+	Set    cmd.SubCommand     `cmd:"set"`
+	Name   string             `cmd:"player"`
+	Node   string             `cmd:"node"`
+	Value  cmd.Optional[bool] `cmd:"value"`
+}
+
+func (c PermPlayerSet) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	value := c.Value.LoadOr(true)
+	defer record(src, o, "perm player set", c.Name, c.Node, fmt.Sprint(value))
+	if err := permission.PermissionEntry().SetPlayerPermission(c.Name, c.Node, value); err != nil {
+		o.Error(err)
+		return
+	}
+	o.Printf("Set permission %v to %v for player %v", c.Node, value, c.Name)
+}
+
+func (PermPlayerSet) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.perm.player")
+}
+
+type PermPlayerUnset struct {
+	Player cmd.SubCommand `cmd:"player"`
+	Unset  cmd.SubCommand `cmd:"unset"`
+	Name   string         `cmd:"player"`
+	Node   string         `cmd:"node"`
+}
+
+func (c PermPlayerUnset) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "perm player unset", c.Name, c.Node)
+	if err := permission.PermissionEntry().UnsetPlayerPermission(c.Name, c.Node); err != nil {
+		o.Error(err)
+		return
+	}
+	o.Printf("Unset permission %v for player %v", c.Node, c.Name)
+}
+
+func (PermPlayerUnset) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.perm.player")
+}
+
+type PermPlayerAddGroup struct {
+	Player   cmd.SubCommand `cmd:"player"`
+	AddGroup cmd.SubCommand `cmd:"addgroup"`
+	Name     string         `cmd:"player"`
+	Group    string         `cmd:"group"`
+}
+
+func (c PermPlayerAddGroup) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "perm player addgroup", c.Name, c.Group)
+	if err := permission.PermissionEntry().AddPlayerGroup(c.Name, c.Group); err != nil {
+		o.Error(err)
+		return
+	}
+	o.Printf("Added player %v to group %v", c.Name, c.Group)
+}
+
+func (PermPlayerAddGroup) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.perm.player")
+}
+
+type PermPlayerRemoveGroup struct {
+	Player      cmd.SubCommand `cmd:"player"`
+	RemoveGroup cmd.SubCommand `cmd:"removegroup"`
+	Name        string         `cmd:"player"`
+	Group       string         `cmd:"group"`
+}
+
+func (c PermPlayerRemoveGroup) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "perm player removegroup", c.Name, c.Group)
+	if err := permission.PermissionEntry().RemovePlayerGroup(c.Name, c.Group); err != nil {
+		o.Error(err)
+		return
+	}
+	o.Printf("Removed player %v from group %v", c.Name, c.Group)
+}
+
+func (PermPlayerRemoveGroup) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.perm.player")
+}
+
+// printPermissions prints the permissions passed to the output passed, one per line and sorted by node.
+func printPermissions(o *cmd.Output, perms map[string]bool) {
+	nodes := make([]string, 0, len(perms))
+	for node, value := range perms {
+		nodes = append(nodes, fmt.Sprintf("%v: %v", node, value))
+	}
+	sort.Strings(nodes)
+	o.Printf("There are %v permissions set:", len(nodes))
+	for _, node := range nodes {
+		o.Print(node)
+	}
+}
@@ -1,15 +1,16 @@
 package cmd
 
 import (
+	"github.com/Blackjack200/GracticeEssential/mhandler"
 	"github.com/Blackjack200/GracticeEssential/permission"
 	"github.com/df-mc/dragonfly/server/cmd"
 )
 
-var AllowImpl = func(s cmd.Source) bool {
-	if t, ok := s.(cmd.NamedTarget); ok {
-		return permission.OpEntry().Has(t.Name())
-	}
-	return false
+// AllowImpl checks if the source passed may run a command that requires the permission node passed. Every
+// command declares its node, such as essential.command.kick, and passes it to AllowImpl in its Allow method.
+// Whether the node is looked up for the source depends on the permission.Capabilities of its kind.
+var AllowImpl = func(s cmd.Source, node string) bool {
+	return permission.PermissionEntry().Allow(s, node)
 }
 
 func Setup() {
@@ -20,13 +21,23 @@ func Setup() {
 	cmd.Register(cmd.New("list", "Lists all online players", nil, List{}))
 	cmd.Register(cmd.New("gc", "Fires garbage collection tasks.", nil, GC{}))
 	cmd.Register(cmd.New("stop", "Stops the server.", nil, Stop{}))
+	cmd.Register(cmd.New("reload", "Reloads the ban list, operators, whitelist, permissions and config.", nil, Reload{}))
 
 	cmd.Register(cmd.New("op", "Grants operator status to a player.", nil, Op{}))
 	cmd.Register(cmd.New("deop", "Revokes operator status from a player.", nil, DeOp{}))
 
 	cmd.Register(cmd.New("banlist", "View all players banned from this server", nil, BanList{}))
 	cmd.Register(cmd.New("ban", "Adds player to banlist.", nil, Ban{}))
+	cmd.Register(cmd.New("tempban", "Adds player to banlist for a duration, such as 30m, 12h or 7d.", nil, TempBan{}))
 	cmd.Register(cmd.New("unban", "Removes player from banlist.", nil, Unban{}))
+	cmd.Register(cmd.New("ban-ip", "Adds an IP address, CIDR range or the IP of a player to banlist.", nil, BanIP{}))
+	cmd.Register(cmd.New("pardon-ip", "Removes an IP address or CIDR range from banlist.", nil, PardonIP{}))
+	cmd.Register(cmd.New("whitelist", "Manages the players allowed to join the server.", nil,
+		WhitelistOn{}, WhitelistOff{}, WhitelistAdd{}, WhitelistRemove{}, WhitelistList{}, WhitelistReload{}))
+	cmd.Register(cmd.New("perm", "Manages the permissions of groups and players.", []string{"permission"},
+		PermCheck{}, PermGroupList{}, PermGroupInfo{}, PermGroupCreate{}, PermGroupDelete{}, PermGroupSet{}, PermGroupUnset{},
+		PermGroupInherit{}, PermGroupUninherit{}, PermPlayerInfo{}, PermPlayerSet{}, PermPlayerUnset{},
+		PermPlayerAddGroup{}, PermPlayerRemoveGroup{}))
 	cmd.Register(cmd.New("kick", "Kicks a player from the server.", nil, Kick{}))
 
 	cmd.Register(cmd.New("difficulty", "Sets the game difficulty", nil, Difficulty{}))
@@ -34,4 +45,8 @@ func Setup() {
 	cmd.Register(cmd.New("gamemode", "Sets your game mode.", []string{"gm"}, GameMode{}))
 
 	cmd.Register(cmd.New("setworldspawn", "Sets the world spawn.", nil, SetWorldSpawn{}))
+
+	cmd.Register(cmd.New("admin", "Opens the admin panel.", nil, Admin{}))
+	cmd.Register(cmd.New("audit", "Shows the administrative commands run, optionally those of a player.", nil, Audit{}, AuditPlayer{}))
+	mhandler.Global().Register(auditHandler{}, mhandler.WithPriority(mhandler.PriorityMonitor))
 }
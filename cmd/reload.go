@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/Blackjack200/GracticeEssential/server"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+type Reload struct{}
+
+func (Reload) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "reload", "")
+	restart, err := server.Reload()
+	if err != nil {
+		o.Error(err)
+		return
+	}
+	o.Print("Reloaded the ban list, operators, whitelist, permissions and config")
+	if len(restart) != 0 {
+		o.Printf("Changed settings that only apply after a restart: %v", strings.Join(restart, ", "))
+	}
+}
+
+func (Reload) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.reload")
+}
@@ -11,6 +11,7 @@ import (
 type SetWorldSpawn struct{}
 
 func (SetWorldSpawn) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "setworldspawn", "")
 	if p, ok := src.(*player.Player); ok {
 		s := cube.PosFromVec3(p.Position())
 		server.Global().World().SetSpawn(s)
@@ -21,5 +22,5 @@ func (SetWorldSpawn) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
 }
 
 func (SetWorldSpawn) Allow(s cmd.Source) bool {
-	return AllowImpl(s)
+	return AllowImpl(s, "essential.command.setworldspawn")
 }
@@ -22,7 +22,7 @@ func (Status) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
 }
 
 func (Status) Allow(s cmd.Source) bool {
-	return AllowImpl(s)
+	return AllowImpl(s, "essential.command.status")
 }
 
 func getMemStats() runtime.MemStats {
@@ -9,6 +9,7 @@ import (
 type Stop struct{}
 
 func (Stop) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "stop", "")
 	out := &cmd.Output{}
 	out.Print("Stopping the server")
 	for p := range server.Global().Players(nil) {
@@ -18,5 +19,5 @@ func (Stop) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
 }
 
 func (Stop) Allow(s cmd.Source) bool {
-	return AllowImpl(s)
+	return AllowImpl(s, "essential.command.stop")
 }
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Blackjack200/GracticeEssential/permission"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+type WhitelistOn struct {
+	On cmd.SubCommand `cmd:"on"`
+}
+
+func (WhitelistOn) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "whitelist on", "")
+	if err := permission.WhitelistEntry().SetEnabled(true); err != nil {
+		o.Error(err)
+		return
+	}
+	o.Print("Turned on the whitelist")
+}
+
+func (WhitelistOn) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.whitelist.on")
+}
+
+type WhitelistOff struct {
+	Off cmd.SubCommand `cmd:"off"`
+}
+
+func (WhitelistOff) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "whitelist off", "")
+	if err := permission.WhitelistEntry().SetEnabled(false); err != nil {
+		o.Error(err)
+		return
+	}
+	o.Print("Turned off the whitelist")
+}
+
+func (WhitelistOff) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.whitelist.off")
+}
+
+type WhitelistAdd struct {
+	Add    cmd.SubCommand `cmd:"add"`
+	Target string
+}
+
+func (w WhitelistAdd) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "whitelist add", w.Target)
+	target := whitelistTarget(w.Target)
+	if target == "" {
+		o.Error("Command argument error")
+		return
+	}
+	if permission.WhitelistEntry().Has(target) {
+		o.Errorf("%v is already whitelisted", target)
+		return
+	}
+	if err := permission.WhitelistEntry().Add(target); err != nil {
+		o.Error(err)
+		return
+	}
+	o.Printf("Added %v to the whitelist", target)
+}
+
+func (WhitelistAdd) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.whitelist.add")
+}
+
+type WhitelistRemove struct {
+	Remove cmd.SubCommand `cmd:"remove"`
+	Target string
+}
+
+func (w WhitelistRemove) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "whitelist remove", w.Target)
+	target := whitelistTarget(w.Target)
+	if !permission.WhitelistEntry().Has(target) {
+		o.Errorf("%v is not whitelisted", target)
+		return
+	}
+	if err := permission.WhitelistEntry().Delete(target); err != nil {
+		o.Error(err)
+		return
+	}
+	o.Printf("Removed %v from the whitelist", target)
+}
+
+func (WhitelistRemove) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.whitelist.remove")
+}
+
+type WhitelistList struct {
+	List cmd.SubCommand `cmd:"list"`
+}
+
+func (WhitelistList) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	entries := permission.WhitelistEntry().GetAll()
+	sort.Strings(entries)
+	state := "off"
+	if permission.WhitelistEntry().Enabled() {
+		state = "on"
+	}
+	o.Printf("The whitelist is %v. There are %v whitelisted players: %v", state, len(entries), strings.Join(entries, ", "))
+}
+
+func (WhitelistList) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.whitelist.list")
+}
+
+type WhitelistReload struct {
+	Reload cmd.SubCommand `cmd:"reload"`
+}
+
+func (WhitelistReload) Run(src cmd.Source, o *cmd.Output, tx *world.Tx) {
+	defer record(src, o, "whitelist reload", "")
+	if err := permission.WhitelistEntry().Reload(); err != nil {
+		o.Error(err)
+		return
+	}
+	o.Print("Reloaded the whitelist")
+}
+
+func (WhitelistReload) Allow(s cmd.Source) bool {
+	return AllowImpl(s, "essential.command.whitelist.reload")
+}
+
+// whitelistTarget returns the whitelist entry for the target passed, which is a name or an XUID, or an IP
+// address or CIDR range in its canonical form.
+func whitelistTarget(target string) string {
+	if address, err := permission.ParseAddress(target); err == nil {
+		return address
+	}
+	return strings.TrimSpace(target)
+}
@@ -1,6 +1,7 @@
 package console
 
 import (
+	"github.com/Blackjack200/GracticeEssential/permission"
 	"github.com/df-mc/dragonfly/server/cmd"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
@@ -16,6 +17,11 @@ func (src source) Name() string {
 	return "CONSOLE"
 }
 
+// SourceKind returns permission.SourceConsole, so that the console is trusted by its kind rather than its name.
+func (src source) SourceKind() permission.SourceKind {
+	return permission.SourceConsole
+}
+
 func (src source) Position() mgl64.Vec3 {
 	return mgl64.Vec3{}
 }
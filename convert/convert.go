@@ -1,12 +1,21 @@
 package convert
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/df-mc/dragonfly/server/world"
 )
 
+// GameModes holds the names of all game modes, as returned by DumpGameMode.
+var GameModes = []string{"survival", "creative", "adventure", "spectator"}
+
+// Difficulties holds the names of all difficulties, as returned by DumpDifficulty.
+var Difficulties = []string{"peaceful", "easy", "normal", "hard"}
+
 func ParseGameMode(v string) (world.GameMode, error) {
 	switch strings.ToLower(v) {
 	case "0", "s", "survival":
@@ -65,6 +74,89 @@ func DumpDifficulty(d world.Difficulty) (string, error) {
 	}
 }
 
+// durationUnits are the units accepted by ParseDuration in addition to those of time.ParseDuration, and used
+// by DumpDuration.
+var durationUnits = []struct {
+	suffix string
+	d      time.Duration
+}{
+	{"w", 7 * 24 * time.Hour},
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+}
+
+// MaxDuration is the longest duration accepted by ParseDuration, which is about 100 years.
+const MaxDuration = 36500 * 24 * time.Hour
+
+// ParseDuration parses a duration such as "30m", "12h" or "1w2d". In addition to the units accepted by
+// time.ParseDuration, it accepts "d" for days and "w" for weeks. The duration must be positive and may not be
+// longer than MaxDuration.
+func ParseDuration(v string) (time.Duration, error) {
+	s := strings.ToLower(strings.TrimSpace(v))
+	var total time.Duration
+	for s != "" {
+		i := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+		if i <= 0 {
+			return 0, fmt.Errorf("invalid duration %v", v)
+		}
+		j := strings.IndexFunc(s[i:], func(r rune) bool { return r >= '0' && r <= '9' })
+		if j == -1 {
+			j = len(s) - i
+		}
+		n, unit := s[:i], s[i:i+j]
+		s = s[i+j:]
+
+		var d time.Duration
+		switch unit {
+		case "w", "d":
+			u := 24 * time.Hour
+			if unit == "w" {
+				u *= 7
+			}
+			// The number is checked before multiplying, as the duration would otherwise overflow.
+			count, err := strconv.ParseInt(n, 10, 64)
+			if errors.Is(err, strconv.ErrRange) || time.Duration(count) > MaxDuration/u {
+				return 0, fmt.Errorf("duration %v is longer than %v", v, DumpDuration(MaxDuration))
+			} else if err != nil {
+				return 0, fmt.Errorf("invalid duration %v", v)
+			}
+			d = time.Duration(count) * u
+		default:
+			var err error
+			if d, err = time.ParseDuration(n + unit); err != nil {
+				return 0, fmt.Errorf("invalid duration %v", v)
+			}
+		}
+		if d > MaxDuration-total {
+			return 0, fmt.Errorf("duration %v is longer than %v", v, DumpDuration(MaxDuration))
+		}
+		total += d
+	}
+	if total <= 0 {
+		return 0, fmt.Errorf("invalid duration %v", v)
+	}
+	return total, nil
+}
+
+// DumpDuration formats a duration as weeks, days, hours, minutes and seconds, such as "1d 2h 30m". Durations
+// are rounded up to whole seconds.
+func DumpDuration(d time.Duration) string {
+	d = (d + time.Second - 1).Truncate(time.Second)
+	if d <= 0 {
+		return "0s"
+	}
+	var parts []string
+	for _, u := range durationUnits {
+		if n := d / u.d; n > 0 {
+			parts = append(parts, strconv.Itoa(int(n))+u.suffix)
+			d -= n * u.d
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
 func MustString(e string, err error) string {
 	if err != nil {
 		panic(err)
@@ -0,0 +1,50 @@
+package convert
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"30m", 30 * time.Minute},
+		{"12h", 12 * time.Hour},
+		{"7d", 7 * 24 * time.Hour},
+		{"1w2d", 9 * 24 * time.Hour},
+		{" 1H30M ", 90 * time.Minute},
+		{"36500d", MaxDuration},
+	}
+	for _, test := range tests {
+		if got, err := ParseDuration(test.in); err != nil || got != test.want {
+			t.Errorf("ParseDuration(%q) = %v, %v, expected %v", test.in, got, err, test.want)
+		}
+	}
+}
+
+func TestParseDurationInvalid(t *testing.T) {
+	for _, in := range []string{"", "0s", "d", "10", "5x", "-5m", "36501d", "9999999999w", "99999999999999999999d", "36500d1s"} {
+		if d, err := ParseDuration(in); err == nil {
+			t.Errorf("ParseDuration(%q) = %v, expected an error", in, d)
+		}
+	}
+}
+
+func TestDumpDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{time.Millisecond, "1s"},
+		{90 * time.Minute, "1h 30m"},
+		{8*24*time.Hour + time.Second, "1w 1d 1s"},
+	}
+	for _, test := range tests {
+		if got := DumpDuration(test.in); got != test.want {
+			t.Errorf("DumpDuration(%v) = %q, expected %q", test.in, got, test.want)
+		}
+	}
+}
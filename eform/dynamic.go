@@ -0,0 +1,98 @@
+package eform
+
+import (
+	"encoding/json"
+)
+
+// Dynamic represents a menu form with a button for every item of a list that is computed each time the form
+// is sent, such as the players online or the entries of a ban list. The callback of the buttons is passed the
+// item that the button was created for.
+// The buttons are split into pages like those of a Paginated menu. The items are computed once for every time
+// the form is sent, so the item passed to the callback is always the one displayed on the button clicked,
+// even if the list changed in the meantime.
+type Dynamic[T any] struct {
+	title, body string
+	items       func(s Submitter) []T
+	button      func(item T) Button
+	onClick     func(s Submitter, item T)
+	pageSize    int
+	onBack      Handler
+	onClose     Handler
+}
+
+// NewDynamic creates a new Dynamic menu form with the title passed. The items function is called to compute
+// the items every time the form is sent, with the Submitter it is sent to. Every item is displayed using the
+// Button returned by the button function, and onClick is called with the item of the button clicked.
+// If the form is sent to a player, the items function is called within the transaction of the player, which
+// should be passed to functions such as server.Server.Players to prevent a deadlock. The player may be obtained
+// using PlayerOf, and its transaction using the Tx method of the player.
+func NewDynamic[T any](title string, items func(s Submitter) []T, button func(item T) Button, onClick func(s Submitter, item T)) Dynamic[T] {
+	return Dynamic[T]{title: title, items: items, button: button, onClick: onClick, pageSize: 10}
+}
+
+// WithBody creates a copy of the Dynamic menu and changes the body displayed to the body passed. The text is
+// formatted following the rules of fmt.Sprintln.
+func (d Dynamic[T]) WithBody(body ...any) Dynamic[T] {
+	d.body = format(body)
+	return d
+}
+
+// WithPageSize creates a copy of the Dynamic menu that shows at most n buttons per page. WithPageSize panics
+// if n is not positive.
+func (d Dynamic[T]) WithPageSize(n int) Dynamic[T] {
+	if n <= 0 {
+		panic("page size must be positive")
+	}
+	d.pageSize = n
+	return d
+}
+
+// OnBack creates a copy of the Dynamic menu with a back button, which calls the handler passed when clicked.
+func (d Dynamic[T]) OnBack(onBack Handler) Dynamic[T] {
+	d.onBack = onBack
+	return d
+}
+
+// OnClose creates a copy of the Dynamic menu and set the form close callback to the passed one.
+func (d Dynamic[T]) OnClose(onClose Handler) Dynamic[T] {
+	d.onClose = onClose
+	return d
+}
+
+// Title returns the title passed to the menu upon construction using NewDynamic().
+func (d Dynamic[T]) Title() string {
+	return d.title
+}
+
+// Paginated computes the items of the menu for the Submitter passed and returns a Paginated menu with their
+// buttons.
+func (d Dynamic[T]) Paginated(s Submitter) Paginated {
+	p := NewPaginated(d.title).WithBody(d.body).WithPageSize(d.pageSize).OnBack(d.onBack).OnClose(d.onClose)
+	for _, item := range d.items(s) {
+		p = p.WithButton(d.button(item), func(s Submitter) {
+			if d.onClick != nil {
+				d.onClick(s, item)
+			}
+		})
+	}
+	return p
+}
+
+// resolve ...
+func (d Dynamic[T]) resolve(s Submitter) Form {
+	return d.Paginated(s)
+}
+
+// MarshalJSON marshals the menu with the items computed for no Submitter. Forms sent using Send or Adapt are
+// computed before they are marshaled instead.
+func (d Dynamic[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Paginated(nil))
+}
+
+// SubmitJSON submits a JSON value to the menu computed for the Submitter passed. Forms sent using Send or Adapt
+// are computed when sent instead, so that the items submitted to are those displayed.
+func (d Dynamic[T]) SubmitJSON(b []byte, submitter Submitter) error {
+	return d.Paginated(submitter).SubmitJSON(b, submitter)
+}
+
+func (Dynamic[T]) __() {}
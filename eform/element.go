@@ -2,7 +2,11 @@ package eform
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/exp/slices"
 )
 
 // Element represents an element that may be added to a Form. Any of the types in this package that implement
@@ -37,6 +41,53 @@ func (Label) haveData() bool {
 	return false
 }
 
+// Header represents a header on a form, displaying text in a larger font than a Label. Like a Label, users
+// cannot submit values to it.
+type Header struct {
+	// Text is the text held by the header. The text may contain Minecraft formatting codes.
+	Text string
+}
+
+// NewHeader creates and returns a new Header with the values passed.
+func NewHeader(text string) Header {
+	return Header{Text: text}
+}
+
+// MarshalJSON ...
+func (h Header) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"type": "header",
+		"text": h.Text,
+	})
+}
+
+// haveData ...
+func (Header) haveData() bool {
+	return false
+}
+
+// Divider represents a horizontal line on a form, separating the elements above it from those below it. Users
+// cannot submit values to it.
+type Divider struct{}
+
+// NewDivider creates and returns a new Divider.
+func NewDivider() Divider {
+	return Divider{}
+}
+
+// MarshalJSON ...
+func (Divider) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"type": "divider",
+		"text": "",
+	})
+}
+
+// haveData ...
+func (Divider) haveData() bool {
+	return false
+}
+
 // Input represents a text input box element. Submitters may write any text in these boxes with no specific
 // length.
 type Input struct {
@@ -48,6 +99,11 @@ type Input struct {
 	// Placeholder is the text displayed in the input box if it does not contain any text filled out by the
 	// user. The text may contain Minecraft formatting codes.
 	Placeholder string
+	// Tooltip is the text displayed when hovering over the info icon shown next to the input. No icon is
+	// shown if it is empty. The text may contain Minecraft formatting codes.
+	Tooltip string
+
+	validate func(string) error
 }
 
 // NewInput creates and returns a new Input with the values passed.
@@ -57,12 +113,12 @@ func NewInput(text, defaultValue, placeholder string) Input {
 
 // MarshalJSON ...
 func (i Input) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]any{
+	return json.Marshal(withTooltip(map[string]any{
 		"type":        "input",
 		"text":        i.Text,
 		"default":     i.Default,
 		"placeholder": i.Placeholder,
-	})
+	}, i.Tooltip))
 }
 
 // haveData ...
@@ -70,6 +126,39 @@ func (Input) haveData() bool {
 	return true
 }
 
+// parse parses the value submitted for the input.
+func (i Input) parse(v any) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("value %v is not allowed for input element", v)
+	}
+	if !utf8.ValidString(s) {
+		return "", fmt.Errorf("value %v is not valid UTF8", v)
+	}
+	return s, nil
+}
+
+// WithValidator creates a copy of the Input with the validator passed, which is called with the text
+// submitted. If it returns an error, the form is sent to the Submitter again with the error displayed.
+func (i Input) WithValidator(validate func(string) error) Input {
+	i.validate = validate
+	return i
+}
+
+// check checks the value submitted for the input using its validator.
+func (i Input) check(v any) error {
+	if i.validate == nil {
+		return nil
+	}
+	return i.validate(v.(string))
+}
+
+// withValue returns a copy of the input with the value passed as default.
+func (i Input) withValue(v any) Element {
+	i.Default = v.(string)
+	return i
+}
+
 // Toggle represents an on-off button element. Submitters may either toggle this on or off, which will then
 // hold a value of true or false respectively.
 type Toggle struct {
@@ -78,6 +167,11 @@ type Toggle struct {
 	// Default is the default value filled out in the input. The user may remove this value and fill out its
 	// own text. The text may contain Minecraft formatting codes.
 	Default bool
+	// Tooltip is the text displayed when hovering over the info icon shown next to the toggle. No icon is
+	// shown if it is empty. The text may contain Minecraft formatting codes.
+	Tooltip string
+
+	validate func(bool) error
 }
 
 // NewToggle creates and returns a new Toggle with the values passed.
@@ -87,11 +181,11 @@ func NewToggle(text string, defaultValue bool) Toggle {
 
 // MarshalJSON ...
 func (t Toggle) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]any{
+	return json.Marshal(withTooltip(map[string]any{
 		"type":    "toggle",
 		"text":    t.Text,
 		"default": t.Default,
-	})
+	}, t.Tooltip))
 }
 
 // haveData ...
@@ -99,6 +193,36 @@ func (Toggle) haveData() bool {
 	return true
 }
 
+// parse parses the value submitted for the toggle.
+func (t Toggle) parse(v any) (bool, error) {
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("value %v is not allowed for toggle element", v)
+	}
+	return b, nil
+}
+
+// WithValidator creates a copy of the Toggle with the validator passed, which is called with the value
+// submitted. If it returns an error, the form is sent to the Submitter again with the error displayed.
+func (t Toggle) WithValidator(validate func(bool) error) Toggle {
+	t.validate = validate
+	return t
+}
+
+// check checks the value submitted for the toggle using its validator.
+func (t Toggle) check(v any) error {
+	if t.validate == nil {
+		return nil
+	}
+	return t.validate(v.(bool))
+}
+
+// withValue returns a copy of the toggle with the value passed as default.
+func (t Toggle) withValue(v any) Element {
+	t.Default = v.(bool)
+	return t
+}
+
 // Slider represents a slider element. Submitters may move the slider to values within the range of the slider
 // to select a value.
 type Slider struct {
@@ -112,6 +236,11 @@ type Slider struct {
 	StepSize float64
 	// Default is the default value filled out for the slider.
 	Default float64
+	// Tooltip is the text displayed when hovering over the info icon shown next to the slider. No icon is
+	// shown if it is empty. The text may contain Minecraft formatting codes.
+	Tooltip string
+
+	validate func(float64) error
 }
 
 // NewSlider creates and returns a new Slider using the values passed.
@@ -121,14 +250,14 @@ func NewSlider(text string, min, max, stepSize, defaultValue float64) Slider {
 
 // MarshalJSON ...
 func (s Slider) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]any{
+	return json.Marshal(withTooltip(map[string]any{
 		"type":    "slider",
 		"text":    s.Text,
 		"min":     s.Min,
 		"max":     s.Max,
 		"step":    s.StepSize,
 		"default": s.Default,
-	})
+	}, s.Tooltip))
 }
 
 // haveData ...
@@ -136,6 +265,40 @@ func (Slider) haveData() bool {
 	return true
 }
 
+// parse parses the value submitted for the slider.
+func (s Slider) parse(v any) (float64, error) {
+	n, ok := v.(json.Number)
+	f, err := n.Float64()
+	if !ok || err != nil {
+		return 0, fmt.Errorf("value %v is not allowed for slider element", v)
+	}
+	if f > s.Max || f < s.Min {
+		return 0, fmt.Errorf("slider value %v is out of range %v-%v", f, s.Min, s.Max)
+	}
+	return f, nil
+}
+
+// WithValidator creates a copy of the Slider with the validator passed, which is called with the value
+// submitted. If it returns an error, the form is sent to the Submitter again with the error displayed.
+func (s Slider) WithValidator(validate func(float64) error) Slider {
+	s.validate = validate
+	return s
+}
+
+// check checks the value submitted for the slider using its validator.
+func (s Slider) check(v any) error {
+	if s.validate == nil {
+		return nil
+	}
+	return s.validate(v.(float64))
+}
+
+// withValue returns a copy of the slider with the value passed as default.
+func (s Slider) withValue(v any) Element {
+	s.Default = v.(float64)
+	return s
+}
+
 // Dropdown represents a dropdown which, when clicked, opens a window with the options set in the Options
 // field. Submitters may select one of the options.
 type Dropdown struct {
@@ -147,6 +310,11 @@ type Dropdown struct {
 	// DefaultIndex is the index in the Options slice that is used as default. When sent to a Submitter, the
 	// value at this index in the Options slice will be selected.
 	DefaultIndex int
+	// Tooltip is the text displayed when hovering over the info icon shown next to the dropdown. No icon is
+	// shown if it is empty. The text may contain Minecraft formatting codes.
+	Tooltip string
+
+	validate func(string) error
 }
 
 // NewDropdown creates and returns new Dropdown using the values passed.
@@ -156,12 +324,12 @@ func NewDropdown(text string, options []string, defaultIndex int) Dropdown {
 
 // MarshalJSON ...
 func (d Dropdown) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]any{
+	return json.Marshal(withTooltip(map[string]any{
 		"type":    "dropdown",
 		"text":    d.Text,
 		"default": d.DefaultIndex,
 		"options": d.Options,
-	})
+	}, d.Tooltip))
 }
 
 // haveData ...
@@ -169,6 +337,34 @@ func (Dropdown) haveData() bool {
 	return true
 }
 
+// parse parses the index submitted for the dropdown and returns the option selected.
+func (d Dropdown) parse(v any) (string, error) {
+	return parseOption("dropdown", d.Options, v)
+}
+
+// WithValidator creates a copy of the Dropdown with the validator passed, which is called with the option
+// selected. If it returns an error, the form is sent to the Submitter again with the error displayed.
+func (d Dropdown) WithValidator(validate func(string) error) Dropdown {
+	d.validate = validate
+	return d
+}
+
+// check checks the option selected in the dropdown using its validator.
+func (d Dropdown) check(v any) error {
+	if d.validate == nil {
+		return nil
+	}
+	return d.validate(v.(string))
+}
+
+// withValue returns a copy of the dropdown with the option passed selected by default.
+func (d Dropdown) withValue(v any) Element {
+	if i := slices.Index(d.Options, v.(string)); i != -1 {
+		d.DefaultIndex = i
+	}
+	return d
+}
+
 // StepSlider represents a slider that has a number of options that may be selected. It is essentially a
 // combination of a Dropdown and a Slider, looking like a slider but having properties like a dropdown.
 type StepSlider Dropdown
@@ -180,12 +376,12 @@ func NewStepSlider(text string, options []string, defaultIndex int) StepSlider {
 
 // MarshalJSON ...
 func (s StepSlider) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]any{
+	return json.Marshal(withTooltip(map[string]any{
 		"type":    "step_slider",
 		"text":    s.Text,
 		"default": s.DefaultIndex,
 		"steps":   s.Options,
-	})
+	}, s.Tooltip))
 }
 
 // haveData ...
@@ -193,6 +389,51 @@ func (StepSlider) haveData() bool {
 	return true
 }
 
+// parse parses the index submitted for the step slider and returns the option selected.
+func (s StepSlider) parse(v any) (string, error) {
+	return parseOption("step slider", s.Options, v)
+}
+
+// WithValidator creates a copy of the StepSlider with the validator passed, which is called with the option
+// selected. If it returns an error, the form is sent to the Submitter again with the error displayed.
+func (s StepSlider) WithValidator(validate func(string) error) StepSlider {
+	s.validate = validate
+	return s
+}
+
+// check checks the option selected in the step slider using its validator.
+func (s StepSlider) check(v any) error {
+	return Dropdown(s).check(v)
+}
+
+// withValue returns a copy of the step slider with the option passed selected by default.
+func (s StepSlider) withValue(v any) Element {
+	return StepSlider(Dropdown(s).withValue(v).(Dropdown))
+}
+
+// withTooltip adds the tooltip passed to the JSON object of an element if it is not empty. Clients that do
+// not support tooltips ignore it.
+func withTooltip(m map[string]any, tooltip string) map[string]any {
+	if tooltip != "" {
+		m["tooltip"] = tooltip
+	}
+	return m
+}
+
+// parseOption parses an index submitted for an element with the options passed and returns the option at
+// that index.
+func parseOption(element string, options []string, v any) (string, error) {
+	n, ok := v.(json.Number)
+	i, err := n.Int64()
+	if !ok || err != nil {
+		return "", fmt.Errorf("value %v is not allowed for %v element", v, element)
+	}
+	if i < 0 || int(i) >= len(options) {
+		return "", fmt.Errorf("%v value %v is out of range %v-%v", element, i, 0, len(options)-1)
+	}
+	return options[i], nil
+}
+
 // Button represents a button added to a Menu or Modal form. The button has text on it and an optional image,
 // which may be either retrieved from a website or the local assets of the game.
 type Button struct {
@@ -212,6 +453,11 @@ func NewButton(text, image string) Button {
 
 // MarshalJSON ...
 func (b Button) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.object())
+}
+
+// object returns the JSON object that the button is marshaled to.
+func (b Button) object() map[string]any {
 	m := map[string]any{"text": b.Text}
 	if b.Image != "" {
 		buttonType := "path"
@@ -220,12 +466,25 @@ func (b Button) MarshalJSON() ([]byte, error) {
 		}
 		m["image"] = map[string]any{"type": buttonType, "data": b.Image}
 	}
-	return json.Marshal(m)
+	return m
+}
+
+// MenuElement represents an element that may be added to the body of a Menu, in between its buttons, using
+// Menu.WithElement. Only clients of newer versions of the game display these elements.
+type MenuElement interface {
+	Element
+	menuElem()
 }
 
 func (Label) elem()      {}
+func (Header) elem()     {}
+func (Divider) elem()    {}
 func (Input) elem()      {}
 func (Toggle) elem()     {}
 func (Slider) elem()     {}
 func (Dropdown) elem()   {}
 func (StepSlider) elem() {}
+
+func (Label) menuElem()   {}
+func (Header) menuElem()  {}
+func (Divider) menuElem() {}
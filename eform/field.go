@@ -0,0 +1,41 @@
+package eform
+
+// DataElement is an Element that holds a value of the type T once submitted, such as an Input, which holds a
+// string, or a Toggle, which holds a bool.
+type DataElement[T any] interface {
+	Element
+	parse(v any) (T, error)
+}
+
+// Field is a typed handle to the value submitted for an element added to a Custom form using Add. Because the
+// type of the value is bound to the element when the form is built, mismatches between elements and the
+// values obtained from them are caught at compile time.
+type Field[T any] struct {
+	index int
+}
+
+// Add creates a copy of the Custom form with the element passed appended to its elements, after which the new
+// Custom form is returned together with a Field that may be used to obtain the value submitted for the element
+// in the callback passed to Custom.OnResponse.
+func Add[T any](f Custom, elem DataElement[T]) (Custom, Field[T]) {
+	f.elements = append(f.elements[:len(f.elements):len(f.elements)], elem)
+	return f, Field[T]{index: len(f.elements) - 1}
+}
+
+// Value returns the value submitted for the element of the Field. The zero value of T is returned if the
+// Response is not one of the form that the Field was created for.
+func (f Field[T]) Value(r Response) T {
+	if f.index < len(r.values) {
+		if v, ok := r.values[f.index].(T); ok {
+			return v
+		}
+	}
+	var zero T
+	return zero
+}
+
+// Response holds the values submitted to a Custom form. The values may be obtained using the Fields returned
+// by Add.
+type Response struct {
+	values []any
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
-	"unicode/utf8"
 )
 
 // Form represents a form that may be sent to a Submitter. The three types of forms, custom forms, menu forms
@@ -20,10 +19,14 @@ type Form interface {
 // Custom represents a form that may be sent to a player and has fields that should be filled out by the
 // player that the form is sent to.
 type Custom struct {
-	title    string
-	elements []Element
-	onClose  Handler
-	onSubmit *reflect.Value
+	title      string
+	elements   []Element
+	onClose    Handler
+	onSubmit   *reflect.Value
+	onResponse func(Submitter, Response)
+	// errs holds the errors of elements that did not pass validation, displayed above the element when the
+	// form is sent to the Submitter again.
+	errs []string
 }
 
 // MarshalJSON ...
@@ -31,10 +34,26 @@ func (f Custom) MarshalJSON() ([]byte, error) {
 	return json.Marshal(map[string]any{
 		"type":    "custom_form",
 		"title":   f.title,
-		"content": f.Elements(),
+		"content": f.content(),
 	})
 }
 
+// content returns the elements of the form as displayed to the Submitter, with a Label holding the
+// validation error inserted above every element that did not pass validation.
+func (f Custom) content() []Element {
+	if len(f.errs) == 0 {
+		return f.Elements()
+	}
+	content := make([]Element, 0, len(f.elements))
+	for i, elem := range f.elements {
+		if f.errs[i] != "" {
+			content = append(content, NewLabel("§c"+f.errs[i]))
+		}
+		content = append(content, elem)
+	}
+	return content
+}
+
 // NewCustom creates a new (custom) form with the title passed and returns it. The title is formatted according to
 // the rules of fmt.Sprintln.
 func NewCustom(title ...any) Custom {
@@ -56,6 +75,8 @@ func (f Custom) OnClose(c Handler) Custom {
 
 // OnSubmit creates a copy of the Menu form and set the form submit callback to the passed one.
 // will panic if passing c isn't a func
+// The parameters of c are only checked at runtime. OnResponse may be used together with Fields returned by Add
+// to have them checked at compile time instead.
 func (f Custom) OnSubmit(c interface{}) Custom {
 	v := reflect.ValueOf(c)
 	if v.Kind() != reflect.Func {
@@ -66,6 +87,13 @@ func (f Custom) OnSubmit(c interface{}) Custom {
 	return f
 }
 
+// OnResponse creates a copy of the Custom form and sets the form submit callback to the one passed. The
+// values submitted may be obtained from the Response using the Fields returned by Add.
+func (f Custom) OnResponse(c func(submitter Submitter, r Response)) Custom {
+	f.onResponse = c
+	return f
+}
+
 // Title returns the formatted title passed when the form was created using NewCustom().
 func (f Custom) Title() string {
 	return f.title
@@ -80,6 +108,8 @@ func (f Custom) Elements() []Element {
 // making sure their values are valid for the form's elements.
 // If the values are valid and can be parsed properly, the fields of the data will be filled out, and
 // the onSubmit callback will be called.
+// If a value is rejected by the validator of its element, the form is sent to the submitter again instead,
+// with the values submitted filled out as defaults and the errors displayed above the elements.
 func (f Custom) SubmitJSON(b []byte, submitter Submitter) error {
 	if b == nil {
 		f.onClose.Call(submitter)
@@ -95,86 +125,91 @@ func (f Custom) SubmitJSON(b []byte, submitter Submitter) error {
 	}
 
 	elem := f.Elements()
+	values := make([]any, len(elem))
 	params := []reflect.Value{reflect.ValueOf(submitter)}
 
+	errs := make([]string, len(elem))
+	invalid := false
+
 	for i := 0; i < len(elem); i++ {
+		if len(f.errs) != 0 && f.errs[i] != "" {
+			// Skip the value of the label holding the error inserted above the element.
+			if len(data) == 0 {
+				return fmt.Errorf("form JSON data array does not have enough values")
+			}
+			data = data[1:]
+		}
 		if len(data) == 0 {
 			return fmt.Errorf("form JSON data array does not have enough values")
 		}
 		val, hasValue, err := f.parseValue(elem[i], data[0])
+		data = data[1:]
+		if err != nil {
+			return fmt.Errorf("error parsing form response value: %w", err)
+		}
 		if !hasValue {
 			continue
 		}
-		if err != nil {
-			return fmt.Errorf("error parsing form response value: %w", err)
+		if c, ok := elem[i].(interface{ check(v any) error }); ok {
+			if err := c.check(val); err != nil {
+				errs[i] = err.Error()
+				invalid = true
+			}
 		}
+		values[i] = val
 		params = append(params, reflect.ValueOf(val))
-		data = data[1:]
 	}
 
-	if f.onSubmit.Type().NumIn() != len(params) {
-		return fmt.Errorf("error form response data: %v parsed, expected %v", len(params), f.onSubmit.Type().NumIn())
+	if invalid {
+		submitter.SendForm(f.reprompt(values, errs))
+		return nil
 	}
 
+	if f.onResponse != nil {
+		f.onResponse(submitter, Response{values: values})
+	}
 	if f.onSubmit != nil {
+		if f.onSubmit.Type().NumIn() != len(params) {
+			return fmt.Errorf("error form response data: %v parsed, expected %v", len(params), f.onSubmit.Type().NumIn())
+		}
 		f.onSubmit.Call(params)
 	}
 	return nil
 }
 
+// reprompt returns a copy of the form with the values passed filled out as defaults of the elements, and
+// the errors passed displayed above the elements that did not pass validation.
+func (f Custom) reprompt(values []any, errs []string) Custom {
+	elements := make([]Element, len(f.elements))
+	for i, elem := range f.elements {
+		if w, ok := elem.(interface{ withValue(v any) Element }); ok && values[i] != nil {
+			elem = w.withValue(values[i])
+		}
+		elements[i] = elem
+	}
+	f.elements, f.errs = elements, errs
+	return f
+}
+
 // parseValue parses a value into the Element passed and returns it as a parsed Value. If the value is not
 // valid for the element, no value, an error is returned.
-func (f Custom) parseValue(elem Element, s any) (interface{}, bool, error) {
-	var ok bool
-	var value interface{}
-
+func (f Custom) parseValue(elem Element, s any) (value interface{}, hasValue bool, err error) {
 	switch element := elem.(type) {
-	case Label:
-		value = nil
-		return value, false, nil
 	case Input:
-		value, ok = s.(string)
-		if !ok {
-			return value, false, fmt.Errorf("value %v is not allowed for input element", s)
-		}
-		if !utf8.ValidString(value.(string)) {
-			return value, false, fmt.Errorf("value %v is not valid UTF8", s)
-		}
+		value, err = element.parse(s)
 	case Toggle:
-		value, ok = s.(bool)
-		if !ok {
-			return value, false, fmt.Errorf("value %v is not allowed for toggle element", s)
-		}
+		value, err = element.parse(s)
 	case Slider:
-		v, ok := s.(json.Number)
-		f, err := v.Float64()
-		if !ok || err != nil {
-			return value, false, fmt.Errorf("value %v is not allowed for slider element", s)
-		}
-		if f > element.Max || f < element.Min {
-			return value, false, fmt.Errorf("slider value %v is out of range %v-%v", f, element.Min, element.Max)
-		}
-		value = f
+		value, err = element.parse(s)
 	case Dropdown:
-		v, ok := s.(json.Number)
-		f, err := v.Int64()
-		if !ok || err != nil {
-			return value, false, fmt.Errorf("value %v is not allowed for dropdown element", s)
-		}
-		if f < 0 || int(f) >= len(element.Options) {
-			return value, false, fmt.Errorf("dropdown value %v is out of range %v-%v", f, 0, len(element.Options)-1)
-		}
-		value = element.Options[f]
+		value, err = element.parse(s)
 	case StepSlider:
-		v, ok := s.(json.Number)
-		f, err := v.Int64()
-		if !ok || err != nil {
-			return value, false, fmt.Errorf("value %v is not allowed for dropdown element", s)
-		}
-		if f < 0 || int(f) >= len(element.Options) {
-			return value, false, fmt.Errorf("dropdown value %v is out of range %v-%v", f, 0, len(element.Options)-1)
-		}
-		value = element.Options[f]
+		value, err = element.parse(s)
+	default:
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
 	}
 	return value, true, nil
 }
@@ -184,7 +219,7 @@ func (f Custom) parseValue(elem Element, s any) (interface{}, bool, error) {
 // will panic if the element is invalid for example button (unavailable for Custom)
 func (f Custom) validateParam(param reflect.Type, elem Element) (valid bool) {
 	switch elem.(type) {
-	case Label:
+	case Label, Header, Divider:
 		return true
 	case Input:
 		return param.Kind() == reflect.String
@@ -222,6 +257,9 @@ func (f Custom) verify() {
 			panic(fmt.Errorf("mismatched params given in OnSubmit"))
 		}
 		for {
+			if elemIndex >= len(elems) {
+				panic(fmt.Errorf("mismatched params given in OnSubmit"))
+			}
 			if v, ok := elems[elemIndex].(interface{ haveData() bool }); ok {
 				if !v.haveData() {
 					elemIndex++
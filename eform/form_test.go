@@ -0,0 +1,93 @@
+package eform
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// recordSubmitter is a Submitter recording the forms sent to it.
+type recordSubmitter struct {
+	forms []Form
+}
+
+func (s *recordSubmitter) SendForm(f Form) {
+	s.forms = append(s.forms, f)
+}
+
+func TestCustomReprompt(t *testing.T) {
+	var amounts []string
+	f, amount := Add(NewCustom("Pay"), NewInput("Amount", "", "").WithValidator(ValidateInt(1, 100)))
+	f = f.OnResponse(func(_ Submitter, r Response) {
+		amounts = append(amounts, amount.Value(r))
+	})
+
+	s := &recordSubmitter{}
+	if err := f.SubmitJSON([]byte(`["1000"]`), s); err != nil {
+		t.Fatal(err)
+	}
+	if len(amounts) != 0 || len(s.forms) != 1 {
+		t.Fatalf("expected the form to be sent again without calling the callback, got %v forms and amounts %v", len(s.forms), amounts)
+	}
+	c, ok := s.forms[0].(Custom)
+	if !ok {
+		t.Fatalf("expected a Custom form to be sent again, got %T", s.forms[0])
+	}
+	content := c.content()
+	if len(content) != 2 {
+		t.Fatalf("expected a label holding the error above the input, got %v elements", len(content))
+	}
+	if l, ok := content[0].(Label); !ok || Translations().translateString(language.Und, l.Text) != "§cMust be between 1 and 100" {
+		t.Fatalf("expected a label holding the error, got %#v", content[0])
+	}
+	tr := NewTranslator(language.AmericanEnglish)
+	tr.Add(language.German, map[string]string{"eform.validate.range": "Muss zwischen {0} und {1} liegen"})
+	if data, err := tr.Localize(c, language.German).MarshalJSON(); err != nil || !strings.Contains(string(data), "Muss zwischen 1 und 100 liegen") {
+		t.Fatalf("expected the error to be translated to the language of the submitter, got %s, %v", data, err)
+	}
+	if i, ok := content[1].(Input); !ok || i.Default != "1000" {
+		t.Fatalf("expected the input to hold the value submitted, got %#v", content[1])
+	}
+
+	// The response to the form sent again holds a value for the label.
+	if err := c.SubmitJSON([]byte(`[null, "50"]`), s); err != nil {
+		t.Fatal(err)
+	}
+	if len(amounts) != 1 || amounts[0] != "50" || len(s.forms) != 1 {
+		t.Fatalf("expected the callback to be called with 50, got amounts %v", amounts)
+	}
+	if err := c.SubmitJSON([]byte(`["50"]`), s); err == nil {
+		t.Fatal("expected an error for a response without a value for the label")
+	}
+}
+
+func TestCustomRepromptGuarded(t *testing.T) {
+	var amounts []string
+	f, amount := Add(NewCustom("Pay"), NewInput("Amount", "", "").WithValidator(ValidateInt(1, 100)))
+	g := Guard(f.OnResponse(func(_ Submitter, r Response) {
+		amounts = append(amounts, amount.Value(r))
+	})).WithTimeout(time.Minute)
+
+	s := &recordSubmitter{}
+	if err := g.SubmitJSON([]byte(`["0"]`), s); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.forms) != 1 {
+		t.Fatalf("expected the form to be sent again, got %v forms", len(s.forms))
+	}
+	reprompt, ok := s.forms[0].(Guarded)
+	if !ok {
+		t.Fatalf("expected the form sent again to be guarded, got %T", s.forms[0])
+	}
+	if err := reprompt.SubmitJSON([]byte(`[null, "5"]`), s); err != nil {
+		t.Fatal(err)
+	}
+	if err := reprompt.SubmitJSON([]byte(`[null, "6"]`), s); err != ErrAnswered {
+		t.Fatalf("expected ErrAnswered for the second response, got %v", err)
+	}
+	if len(amounts) != 1 || amounts[0] != "5" {
+		t.Fatalf("expected the callback to be called once with 5, got amounts %v", amounts)
+	}
+}
@@ -0,0 +1,203 @@
+package eform
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+var (
+	// ErrAnswered is returned by Guarded.SubmitJSON if a response is submitted to a form that was already
+	// answered or closed.
+	ErrAnswered = errors.New("form was already answered")
+	// ErrExpired is returned by Guarded.SubmitJSON if a response is submitted to a form after it expired.
+	ErrExpired = errors.New("form expired before it was answered")
+)
+
+// The states of a Guarded form.
+const (
+	guardOpen int32 = iota
+	guardAnswered
+	guardExpired
+)
+
+// Guarded is a Form that accepts only a single response, which may be a submission or the form being closed,
+// and optionally only until it expires. Any response after that is rejected without calling the callbacks of
+// the Form it guards.
+// Copies of a Guarded form share their state, so Guard should be called for every form sent.
+type Guarded struct {
+	f        Form
+	deadline time.Time
+	onExpire Handler
+	// state is guardOpen until the form is answered or expires. A Custom form re-prompted because of invalid
+	// values is guarded with a new state, but shares the expiry of the form it was re-prompted from.
+	state  *atomic.Int32
+	expiry *expiry
+}
+
+// expiry holds the timer that expires a Guarded form at its deadline.
+type expiry struct {
+	expired atomic.Bool
+	mu      sync.Mutex
+	timer   *time.Timer
+	fire    func()
+}
+
+// Guard returns a Guarded form for the Form passed, which does not expire until WithTimeout or WithDeadline
+// is called.
+func Guard(f Form) Guarded {
+	return Guarded{f: f, state: &atomic.Int32{}, expiry: &expiry{}}
+}
+
+// WithTimeout creates a copy of the Guarded form that expires after the duration passed, counting from the
+// moment WithTimeout is called.
+func (g Guarded) WithTimeout(d time.Duration) Guarded {
+	return g.WithDeadline(time.Now().Add(d))
+}
+
+// WithDeadline creates a copy of the Guarded form that expires at the time passed.
+func (g Guarded) WithDeadline(t time.Time) Guarded {
+	g.deadline = t
+	return g
+}
+
+// OnExpire creates a copy of the Guarded form and sets the callback called when the form expires before it was
+// answered. It is called at most once, at the deadline or when Expire is called if the form was sent using
+// Send, SendHandle or a Submitter passed to a form callback, and otherwise when the Submitter responds to the
+// form after it expired. If the Submitter is a player, the callback is called within its transaction.
+func (g Guarded) OnExpire(onExpire Handler) Guarded {
+	g.onExpire = onExpire
+	return g
+}
+
+// Expire makes the form expire immediately, if it was not yet answered. It may be used to withdraw the form,
+// for example when a trade it confirms was cancelled.
+func (g Guarded) Expire() {
+	g.expiry.expired.Store(true)
+	g.expiry.mu.Lock()
+	fire := g.expiry.fire
+	g.expiry.stopNoLock()
+	g.expiry.mu.Unlock()
+	if fire != nil {
+		fire()
+	}
+}
+
+// Expired checks if the form expired, either because its deadline passed or because Expire was called.
+func (g Guarded) Expired() bool {
+	return g.state.Load() == guardExpired || g.expiry.expired.Load() || (!g.deadline.IsZero() && time.Now().After(g.deadline))
+}
+
+// Answered checks if a response, including the form being closed, was submitted to the form.
+func (g Guarded) Answered() bool {
+	return g.state.Load() == guardAnswered
+}
+
+// MarshalJSON ...
+func (g Guarded) MarshalJSON() ([]byte, error) {
+	return g.f.MarshalJSON()
+}
+
+// SubmitJSON submits the response to the form it guards if it is the first response and the form has not yet
+// expired. ErrAnswered or ErrExpired is returned otherwise, which is ignored if the form was sent using Send or
+// Adapt.
+func (g Guarded) SubmitJSON(b []byte, submitter Submitter) error {
+	if g.Expired() {
+		if g.state.CompareAndSwap(guardOpen, guardExpired) {
+			g.onExpire.Call(submitter)
+		}
+		return ErrExpired
+	}
+	if !g.state.CompareAndSwap(guardOpen, guardAnswered) {
+		if g.state.Load() == guardExpired {
+			return ErrExpired
+		}
+		return ErrAnswered
+	}
+	g.expiry.stop()
+	return g.f.SubmitJSON(b, guardSubmitter{Submitter: submitter, g: g})
+}
+
+// resolve arms the timer that expires the form at its deadline, so that the expiry callback is called with
+// the Submitter passed even if it never responds.
+func (g Guarded) resolve(s Submitter) Form {
+	g.f = resolve(g.f, s)
+	if s != nil && g.onExpire != nil {
+		g.expiry.arm(g, s)
+	}
+	return g
+}
+
+func (Guarded) __() {}
+
+// arm makes the form passed expire at its deadline, calling its expiry callback with the Submitter passed,
+// replacing the Guarded form and Submitter the timer was last armed with.
+func (e *expiry) arm(g Guarded, s Submitter) {
+	call := func() {
+		g.onExpire.Call(s)
+	}
+	if p, ok := PlayerOf(s); ok {
+		// The player is only valid within its transaction, so the callback is called in a new transaction.
+		h := p.H()
+		call = func() {
+			h.ExecWorld(func(_ *world.Tx, ent world.Entity) {
+				g.onExpire.Call(Player{Player: ent.(*player.Player)})
+			})
+		}
+	}
+	fire := func() {
+		if g.state.CompareAndSwap(guardOpen, guardExpired) {
+			call()
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stopNoLock()
+	e.fire = fire
+	if !g.deadline.IsZero() {
+		e.timer = time.AfterFunc(time.Until(g.deadline), fire)
+	}
+}
+
+// stop stops the timer of the expiry, after the form was answered.
+func (e *expiry) stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stopNoLock()
+	e.fire = nil
+}
+
+func (e *expiry) stopNoLock() {
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+	}
+}
+
+// guardSubmitter is the Submitter passed to the callbacks of a Guarded form.
+type guardSubmitter struct {
+	Submitter
+	g Guarded
+}
+
+// SendForm sends the Form passed to the Submitter. A Custom form re-prompted because of invalid values is
+// guarded again with the same deadline, so that it may be answered once more. It expires together with the
+// form it was re-prompted from.
+func (s guardSubmitter) SendForm(f Form) {
+	if c, ok := f.(Custom); ok && len(c.errs) != 0 {
+		g := s.g
+		g.f, g.state = f, &atomic.Int32{}
+		f = g
+	}
+	s.Submitter.SendForm(f)
+}
+
+// unwrap ...
+func (s guardSubmitter) unwrap() Submitter {
+	return s.Submitter
+}
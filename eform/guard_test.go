@@ -0,0 +1,129 @@
+package eform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/player/form"
+)
+
+// formSubmitter is a form.Submitter recording the forms sent to it.
+type formSubmitter struct {
+	forms []form.Form
+}
+
+func (s *formSubmitter) SendForm(f form.Form) {
+	s.forms = append(s.forms, f)
+}
+
+func (s *formSubmitter) CloseForm() {}
+
+func TestAdaptGuardedExpired(t *testing.T) {
+	var clicked, expired int
+	g := Guard(NewMenu("Confirm").WithButton(NewButton("Yes", ""), func(Submitter) {
+		clicked++
+	})).WithTimeout(-time.Second).OnExpire(func(Submitter) {
+		expired++
+	})
+
+	f, s := Adapt(g), &formSubmitter{}
+	if err := f.SubmitJSON([]byte("0"), s, nil); err != nil {
+		t.Fatalf("expected the response to the expired form to be ignored, got %v", err)
+	}
+	if err := f.SubmitJSON([]byte("0"), s, nil); err != nil {
+		t.Fatalf("expected the second response to the form to be ignored, got %v", err)
+	}
+	if clicked != 0 || expired != 1 {
+		t.Fatalf("expected the button not to be clicked and the form to expire once, got %v clicks and %v expirations", clicked, expired)
+	}
+}
+
+func TestAdaptGuardedAnswered(t *testing.T) {
+	var clicked int
+	g := Guard(NewMenu("Confirm").WithButton(NewButton("Yes", ""), func(Submitter) {
+		clicked++
+	})).WithTimeout(time.Minute)
+
+	f, s := Adapt(g), &formSubmitter{}
+	for i := 0; i < 2; i++ {
+		if err := f.SubmitJSON([]byte("0"), s, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if clicked != 1 {
+		t.Fatalf("expected the button to be clicked once, got %v", clicked)
+	}
+	if err := f.SubmitJSON([]byte(`"yes"`), s, nil); err != nil {
+		t.Fatalf("expected the response to the answered form to be ignored, got %v", err)
+	}
+	if err := Adapt(NewMenu("Menu")).SubmitJSON([]byte(`"yes"`), s, nil); err == nil {
+		t.Fatal("expected an error for an invalid response to a form that is not guarded")
+	}
+}
+
+func TestGuardedExpire(t *testing.T) {
+	s := &recordSubmitter{}
+	var expired []Submitter
+	g := Guard(NewMenu("Trade")).OnExpire(func(s Submitter) {
+		expired = append(expired, s)
+	})
+	// Sending the form passes it the Submitter it is sent to.
+	f := resolve(g, s)
+
+	g.Expire()
+	g.Expire()
+	if len(expired) != 1 || expired[0] != s {
+		t.Fatalf("expected the expiry callback to be called once with the submitter, got %v", expired)
+	}
+	if err := f.SubmitJSON(nil, s); err != ErrExpired {
+		t.Fatalf("expected ErrExpired for a response to the expired form, got %v", err)
+	}
+	if len(expired) != 1 {
+		t.Fatalf("expected the expiry callback not to be called again, got %v calls", len(expired))
+	}
+}
+
+func TestGuardedDeadline(t *testing.T) {
+	s := &recordSubmitter{}
+	expired := make(chan Submitter, 2)
+	g := Guard(NewMenu("Trade")).WithTimeout(10 * time.Millisecond).OnExpire(func(s Submitter) {
+		expired <- s
+	})
+	f := resolve(g, s)
+
+	select {
+	case got := <-expired:
+		if got != s {
+			t.Fatalf("expected the expiry callback to be called with the submitter, got %v", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the expiry callback to be called at the deadline without a response")
+	}
+	if err := f.SubmitJSON(nil, s); err != ErrExpired {
+		t.Fatalf("expected ErrExpired for a response after the deadline, got %v", err)
+	}
+	if len(expired) != 0 {
+		t.Fatal("expected the expiry callback to be called once")
+	}
+}
+
+func TestGuardedAnsweredBeforeDeadline(t *testing.T) {
+	s := &recordSubmitter{}
+	g := Guard(NewMenu("Trade")).WithTimeout(time.Minute).OnExpire(func(Submitter) {
+		t.Error("expected the expiry callback not to be called for an answered form")
+	})
+	f := resolve(g, s)
+	if g.expiry.timer == nil {
+		t.Fatal("expected a timer to be armed when the form is sent")
+	}
+	if err := f.SubmitJSON(nil, s); err != nil {
+		t.Fatal(err)
+	}
+	if g.expiry.timer != nil {
+		t.Fatal("expected the timer to be stopped once the form is answered")
+	}
+	g.Expire()
+	if !g.Answered() {
+		t.Fatal("expected the form to stay answered")
+	}
+}
@@ -0,0 +1,383 @@
+package eform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pelletier/go-toml"
+	"golang.org/x/exp/slices"
+)
+
+// Definition is the declarative definition of a form, as read from a JSON or TOML file by a Loader.
+type Definition struct {
+	// Type is the type of the form, which is one of "menu", "modal" and "custom".
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	// Body is the body of a menu or modal form. It is not used by custom forms.
+	Body string `json:"body"`
+	// Buttons are the buttons of a menu form, or the two buttons of a modal form. If a modal form has no
+	// buttons, the default 'yes' and 'no' buttons are used.
+	Buttons []ButtonDefinition `json:"buttons"`
+	// Elements are the elements of a custom form.
+	Elements []ElementDefinition `json:"elements"`
+	// Submit is the name of the submit action called when a custom form is submitted.
+	Submit string `json:"submit"`
+	// Close is the name of the action called when the form is closed.
+	Close string `json:"close"`
+	// Translate specifies if the texts of the form, including those of its buttons and elements, are keys of
+	// translations, which are translated to the language of the player that the form is sent to. The default
+	// values of elements are not translated, and the options submitted for dropdowns and step sliders are
+	// passed to the submit action as their keys.
+	Translate bool `json:"translate"`
+}
+
+// ButtonDefinition is the declarative definition of a button of a menu or modal form. A button may either be
+// bound to a named action or to a command, which is executed by the player that clicked the button.
+type ButtonDefinition struct {
+	Text  string `json:"text"`
+	Image string `json:"image"`
+	// Action is the name of the action called when the button is clicked.
+	Action string `json:"action"`
+	// Command is the command line executed by the player clicking the button, such as "/gamemode creative".
+	// The leading slash is optional. Occurrences of {player} are replaced with the name of the player.
+	Command string `json:"command"`
+}
+
+// ElementDefinition is the declarative definition of an element of a custom form.
+type ElementDefinition struct {
+	// Type is the type of the element, which is one of "label", "header", "divider", "input", "toggle",
+	// "slider", "dropdown" and "step_slider".
+	Type string `json:"type"`
+	// Name is the name that the value submitted for the element is found under in the Values passed to the
+	// submit action.
+	Name        string `json:"name"`
+	Text        string `json:"text"`
+	Placeholder string `json:"placeholder"`
+	Tooltip     string `json:"tooltip"`
+	// Default is the default value of the element. For dropdowns and step sliders, it may be either the
+	// index or the text of the option selected by default.
+	Default any      `json:"default"`
+	Min     float64  `json:"min"`
+	Max     float64  `json:"max"`
+	Step    float64  `json:"step"`
+	Options []string `json:"options"`
+}
+
+// Values holds the values submitted for the named elements of a custom form built by a Loader, with the
+// names of the elements as keys. The values are of the same types as those of the Fields returned by Add.
+type Values map[string]any
+
+// Loader builds forms from Definitions, binding their buttons to actions registered to it. The action "back"
+// is registered by default and returns to the previous form on the navigation Stack of the Submitter.
+type Loader struct {
+	mu      sync.RWMutex
+	actions map[string]Handler
+	submits map[string]func(Submitter, Values)
+}
+
+// NewLoader returns a new Loader with no actions registered other than "back".
+func NewLoader() *Loader {
+	return &Loader{
+		actions: map[string]Handler{"back": Back},
+		submits: map[string]func(Submitter, Values){},
+	}
+}
+
+// Action registers the Handler passed under the name passed, so that buttons of forms loaded afterwards may be
+// bound to it. The Loader is returned so that calls may be chained.
+func (l *Loader) Action(name string, h Handler) *Loader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.actions[name] = h
+	return l
+}
+
+// Submit registers the submit action passed under the name passed, so that custom forms loaded afterwards may
+// be bound to it. The Loader is returned so that calls may be chained.
+func (l *Loader) Submit(name string, f func(Submitter, Values)) *Loader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.submits[name] = f
+	return l
+}
+
+// Load reads the form definition at the path passed and builds a form from it. The format of the file is
+// determined by its extension, which must be either .json or .toml.
+func (l *Loader) Load(path string) (Form, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f Form
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		f, err = l.LoadJSON(data)
+	case ".toml":
+		f, err = l.LoadTOML(data)
+	default:
+		return nil, fmt.Errorf("unsupported form definition format %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	return f, nil
+}
+
+// LoadJSON builds a form from the JSON encoded Definition passed.
+func (l *Loader) LoadJSON(data []byte) (Form, error) {
+	var d Definition
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("error decoding form definition: %w", err)
+	}
+	return l.Build(d)
+}
+
+// LoadTOML builds a form from the TOML encoded Definition passed.
+func (l *Loader) LoadTOML(data []byte) (Form, error) {
+	tree, err := toml.LoadBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding form definition: %w", err)
+	}
+	// The TOML is converted to JSON first, so that integers may be used for the numbers of the definition
+	// and both formats are decoded the same way.
+	data, err = json.Marshal(tree.ToMap())
+	if err != nil {
+		return nil, fmt.Errorf("error decoding form definition: %w", err)
+	}
+	return l.LoadJSON(data)
+}
+
+// Build builds a form from the Definition passed. An error is returned if the Definition is invalid or refers
+// to actions that are not registered.
+func (l *Loader) Build(d Definition) (Form, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	onClose, err := l.action(d.Close)
+	if err != nil {
+		return nil, fmt.Errorf("close: %w", err)
+	}
+	text := func(s string) string {
+		if d.Translate && s != "" {
+			return T(s)
+		}
+		return s
+	}
+	switch d.Type {
+	case "menu":
+		m := NewMenu(text(d.Title)).WithBody(text(d.Body)).OnClose(onClose)
+		for i, btn := range d.Buttons {
+			h, err := l.button(btn)
+			if err != nil {
+				return nil, fmt.Errorf("button %v: %w", i, err)
+			}
+			m = m.WithButton(NewButton(text(btn.Text), btn.Image), h)
+		}
+		return m, nil
+	case "modal":
+		m := NewModal(text(d.Title)).WithBody(text(d.Body)).OnClose(onClose)
+		switch len(d.Buttons) {
+		case 0:
+			return m, nil
+		case 2:
+		default:
+			return nil, fmt.Errorf("modal form must have 0 or 2 buttons, got %v", len(d.Buttons))
+		}
+		h1, err := l.button(d.Buttons[0])
+		if err != nil {
+			return nil, fmt.Errorf("button 0: %w", err)
+		}
+		h2, err := l.button(d.Buttons[1])
+		if err != nil {
+			return nil, fmt.Errorf("button 1: %w", err)
+		}
+		return m.WithButton1(NewButton(text(d.Buttons[0].Text), ""), h1).WithButton2(NewButton(text(d.Buttons[1].Text), ""), h2), nil
+	case "custom":
+		f := NewCustom(text(d.Title)).OnClose(onClose)
+		names := make(map[string]int)
+		for i, e := range d.Elements {
+			elem, err := buildElement(e, text)
+			if err != nil {
+				return nil, fmt.Errorf("element %v: %w", i, err)
+			}
+			if e.Name != "" {
+				if _, ok := names[e.Name]; ok {
+					return nil, fmt.Errorf("element %v: duplicate name %q", i, e.Name)
+				}
+				names[e.Name] = i
+			}
+			f = f.WithElements(elem)
+		}
+		if d.Submit == "" {
+			return f, nil
+		}
+		submit, ok := l.submits[d.Submit]
+		if !ok {
+			return nil, fmt.Errorf("unknown submit action %q", d.Submit)
+		}
+		return f.OnResponse(func(s Submitter, r Response) {
+			values := make(Values, len(names))
+			for name, i := range names {
+				values[name] = r.values[i]
+				if v, ok := r.values[i].(string); ok && d.Translate && isOption(d.Elements[i]) {
+					values[name] = strings.TrimSuffix(strings.TrimPrefix(v, string(translationStart)), string(translationEnd))
+				}
+			}
+			submit(s, values)
+		}), nil
+	}
+	return nil, fmt.Errorf("unknown form type %q", d.Type)
+}
+
+// action returns the action registered under the name passed. A nil Handler is returned for an empty name.
+func (l *Loader) action(name string) (Handler, error) {
+	if name == "" {
+		return nil, nil
+	}
+	h, ok := l.actions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown action %q", name)
+	}
+	return h, nil
+}
+
+// button returns the Handler called when the button with the Definition passed is clicked.
+func (l *Loader) button(btn ButtonDefinition) (Handler, error) {
+	if btn.Command == "" {
+		return l.action(btn.Action)
+	}
+	if btn.Action != "" {
+		return nil, fmt.Errorf("button has both an action and a command")
+	}
+	return Command(btn.Command), nil
+}
+
+// Command returns a Handler that makes the player submitting the form execute the command line passed, as if
+// it was typed in chat. The leading slash is optional. Occurrences of {player} in the command line are
+// replaced with the name of the player, quoted if it holds spaces, so that it is always parsed as a single
+// argument. The Handler does nothing if the Submitter is not a player.
+func Command(commandLine string) Handler {
+	if !strings.HasPrefix(commandLine, "/") {
+		commandLine = "/" + commandLine
+	}
+	return func(s Submitter) {
+		if p, ok := PlayerOf(s); ok {
+			p.ExecuteCommand(strings.ReplaceAll(commandLine, "{player}", quoteArg(p.Name())))
+		}
+	}
+}
+
+// quoteArg quotes the command argument passed if it holds spaces or quotes, following the rules dragonfly
+// uses to split command lines into arguments, which are those of CSV with spaces as separator.
+func quoteArg(arg string) string {
+	if !strings.ContainsAny(arg, ` "`) {
+		return arg
+	}
+	return `"` + strings.ReplaceAll(arg, `"`, `""`) + `"`
+}
+
+// isOption checks if the element with the Definition passed is a dropdown or step slider.
+func isOption(e ElementDefinition) bool {
+	return e.Type == "dropdown" || e.Type == "step_slider"
+}
+
+// buildElement builds the Element with the Definition passed, passing its texts through the text function.
+func buildElement(e ElementDefinition, text func(string) string) (Element, error) {
+	switch e.Type {
+	case "label":
+		return NewLabel(text(e.Text)), nil
+	case "header":
+		return NewHeader(text(e.Text)), nil
+	case "divider":
+		return NewDivider(), nil
+	case "input":
+		def, ok := e.Default.(string)
+		if !ok && e.Default != nil {
+			return nil, fmt.Errorf("default value of input must be a string")
+		}
+		i := NewInput(text(e.Text), def, text(e.Placeholder))
+		i.Tooltip = text(e.Tooltip)
+		return i, nil
+	case "toggle":
+		def, ok := e.Default.(bool)
+		if !ok && e.Default != nil {
+			return nil, fmt.Errorf("default value of toggle must be a bool")
+		}
+		t := NewToggle(text(e.Text), def)
+		t.Tooltip = text(e.Tooltip)
+		return t, nil
+	case "slider":
+		if e.Min > e.Max {
+			return nil, fmt.Errorf("slider minimum %v is greater than maximum %v", e.Min, e.Max)
+		}
+		step := e.Step
+		if step == 0 {
+			step = 1
+		}
+		def, ok := number(e.Default)
+		if !ok {
+			if e.Default != nil {
+				return nil, fmt.Errorf("default value of slider must be a number")
+			}
+			def = e.Min
+		}
+		sl := NewSlider(text(e.Text), e.Min, e.Max, step, def)
+		sl.Tooltip = text(e.Tooltip)
+		return sl, nil
+	case "dropdown", "step_slider":
+		if len(e.Options) == 0 {
+			return nil, fmt.Errorf("%v must have at least one option", e.Type)
+		}
+		def, err := optionIndex(e.Options, e.Default)
+		if err != nil {
+			return nil, err
+		}
+		options := make([]string, len(e.Options))
+		for i, option := range e.Options {
+			options[i] = text(option)
+		}
+		d := NewDropdown(text(e.Text), options, def)
+		d.Tooltip = text(e.Tooltip)
+		if e.Type == "dropdown" {
+			return d, nil
+		}
+		return StepSlider(d), nil
+	}
+	return nil, fmt.Errorf("unknown element type %q", e.Type)
+}
+
+// optionIndex returns the index of the default option passed, which may be either an index or the text of the
+// option.
+func optionIndex(options []string, def any) (int, error) {
+	if def == nil {
+		return 0, nil
+	}
+	if s, ok := def.(string); ok {
+		if i := slices.Index(options, s); i != -1 {
+			return i, nil
+		}
+		return 0, fmt.Errorf("default option %q is not one of the options", s)
+	}
+	f, ok := number(def)
+	if !ok || f != float64(int(f)) || f < 0 || int(f) >= len(options) {
+		return 0, fmt.Errorf("default option %v is not a valid option index", def)
+	}
+	return int(f), nil
+}
+
+// number converts the number passed, such as one decoded from JSON, to a float64.
+func number(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
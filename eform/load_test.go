@@ -0,0 +1,24 @@
+package eform
+
+import (
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestQuoteArg(t *testing.T) {
+	for _, name := range []string{"Steve", "Some Player", `Odd "Name"`} {
+		line := strings.ReplaceAll("tell {player} hello there", "{player}", quoteArg(name))
+		// Command lines are split into arguments by dragonfly in the same way.
+		r := csv.NewReader(strings.NewReader(line))
+		r.Comma, r.LazyQuotes = ' ', true
+		args, err := r.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"tell", name, "hello", "there"}; !slices.Equal(args, want) {
+			t.Errorf("expected arguments %q, got %q", want, args)
+		}
+	}
+}
@@ -15,9 +15,16 @@ type buttonData struct {
 type Menu struct {
 	title, body string
 	btnData     []buttonData
+	elements    []menuElement
 	onClose     Handler
 }
 
+// menuElement is a MenuElement added to the body of a Menu after the button with the index before.
+type menuElement struct {
+	before int
+	elem   MenuElement
+}
+
 // NewMenu creates a new Menu form. The
 // title passed is formatted following the rules of fmt.Sprintln.
 func NewMenu(title ...any) Menu {
@@ -27,14 +34,45 @@ func NewMenu(title ...any) Menu {
 
 // MarshalJSON ...
 func (m Menu) MarshalJSON() ([]byte, error) {
+	if len(m.elements) == 0 {
+		return json.Marshal(map[string]any{
+			"type":    "form",
+			"title":   m.title,
+			"content": m.body,
+			"buttons": m.Buttons(),
+		})
+	}
+	elements := make([]any, 0, len(m.btnData)+len(m.elements))
+	next := 0
+	for i, data := range m.btnData {
+		for ; next < len(m.elements) && m.elements[next].before == i; next++ {
+			elements = append(elements, m.elements[next].elem)
+		}
+		elements = append(elements, menuButton{data.btn})
+	}
+	for _, e := range m.elements[next:] {
+		elements = append(elements, e.elem)
+	}
 	return json.Marshal(map[string]any{
-		"type":    "form",
-		"title":   m.title,
-		"content": m.body,
-		"buttons": m.Buttons(),
+		"type":     "form",
+		"title":    m.title,
+		"content":  m.body,
+		"elements": elements,
 	})
 }
 
+// menuButton is a Button in the elements of a Menu.
+type menuButton struct {
+	Button
+}
+
+// MarshalJSON ...
+func (b menuButton) MarshalJSON() ([]byte, error) {
+	m := b.object()
+	m["type"] = "button"
+	return json.Marshal(m)
+}
+
 // WithBody creates a copy of the Menu form and changes its body to the body passed, after which the new Menu
 // form is returned. The text is formatted following the rules of fmt.Sprintln.
 func (m Menu) WithBody(body ...any) Menu {
@@ -49,6 +87,14 @@ func (m Menu) WithButton(btn Button, onClick Handler) Menu {
 	return m
 }
 
+// WithElement creates a copy of the Menu form and appends the element passed after the existing buttons and
+// elements, after which the new Menu form is returned. Menus with elements are sent in a format that only
+// clients of newer versions of the game support.
+func (m Menu) WithElement(elem MenuElement) Menu {
+	m.elements = append(m.elements[:len(m.elements):len(m.elements)], menuElement{before: len(m.btnData), elem: elem})
+	return m
+}
+
 // OnClose creates a copy of the Menu form and set the form close callback to the passed one.
 func (m Menu) OnClose(onClose Handler) Menu {
 	m.onClose = onClose
@@ -66,6 +66,12 @@ func (m Modal) WithButton2(btn Button, onClick Handler) Modal {
 	return m
 }
 
+// OnClose creates a copy of the Modal form and set the form close callback to the passed one.
+func (m Modal) OnClose(onClose Handler) Modal {
+	m.onClose = onClose
+	return m
+}
+
 // Title returns the formatted title passed to the menu upon construction using NewModal().
 func (m Modal) Title() string {
 	return m.title
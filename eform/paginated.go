@@ -0,0 +1,186 @@
+package eform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sandertv/gophertunnel/minecraft/text"
+)
+
+// Paginated represents a menu form with more buttons than fit on a single Menu. The buttons are split into
+// pages of Menus, with buttons added to navigate to the previous and next page. Optionally, a search button
+// is added that opens a Custom form to filter the buttons by their text.
+// Paginated implements Form: sending it sends its first page.
+type Paginated struct {
+	title, body string
+	btnData     []buttonData
+	pageSize    int
+	search      bool
+	nav         navigation
+	onBack      Handler
+	onClose     Handler
+	// unfiltered is the menu that a menu returned by Filter was created from.
+	unfiltered *Paginated
+}
+
+// navigation holds the buttons used to navigate between the pages of a Paginated menu.
+type navigation struct {
+	previous, next, back, search Button
+}
+
+// NewPaginated creates a new Paginated menu form with 10 buttons per page. The title passed is formatted
+// following the rules of fmt.Sprintln.
+func NewPaginated(title ...any) Paginated {
+	return Paginated{
+		title:    format(title),
+		pageSize: 10,
+		nav: navigation{
+			previous: NewButton("« Previous page", ""),
+			next:     NewButton("Next page »", ""),
+			back:     NewButton("Back", ""),
+			search:   NewButton("Search", ""),
+		},
+	}
+}
+
+// WithBody creates a copy of the Paginated menu and changes the body displayed on every page to the body
+// passed. The text is formatted following the rules of fmt.Sprintln.
+func (p Paginated) WithBody(body ...any) Paginated {
+	p.body = format(body)
+	return p
+}
+
+// WithButton creates a copy of the Paginated menu and appends the button passed to the existing buttons.
+func (p Paginated) WithButton(btn Button, onClick Handler) Paginated {
+	p.btnData = append(p.btnData[:len(p.btnData):len(p.btnData)], buttonData{btn, onClick})
+	return p
+}
+
+// WithPageSize creates a copy of the Paginated menu that shows at most n buttons per page, not counting the
+// navigation buttons. WithPageSize panics if n is not positive.
+func (p Paginated) WithPageSize(n int) Paginated {
+	if n <= 0 {
+		panic("page size must be positive")
+	}
+	p.pageSize = n
+	return p
+}
+
+// WithSearch creates a copy of the Paginated menu with a search button on every page, which opens a form to
+// filter the buttons by their text.
+func (p Paginated) WithSearch() Paginated {
+	p.search = true
+	return p
+}
+
+// WithNavigation creates a copy of the Paginated menu with the buttons passed used to navigate to the previous
+// page, the next page, back to the form set using OnBack, and to the search form.
+func (p Paginated) WithNavigation(previous, next, back, search Button) Paginated {
+	p.nav = navigation{previous: previous, next: next, back: back, search: search}
+	return p
+}
+
+// OnBack creates a copy of the Paginated menu with a back button added to every page, which calls the handler
+// passed when clicked.
+func (p Paginated) OnBack(onBack Handler) Paginated {
+	p.onBack = onBack
+	return p
+}
+
+// OnClose creates a copy of the Paginated menu and set the form close callback to the passed one.
+func (p Paginated) OnClose(onClose Handler) Paginated {
+	p.onClose = onClose
+	return p
+}
+
+// Title returns the formatted title passed to the menu upon construction using NewPaginated().
+func (p Paginated) Title() string {
+	return p.title
+}
+
+// Pages returns the number of pages of the Paginated menu. It is always at least 1.
+func (p Paginated) Pages() int {
+	return max(1, (len(p.btnData)+p.pageSize-1)/p.pageSize)
+}
+
+// Page returns the Menu of the page with the index passed, starting at 0. The index is clamped to the range
+// of pages available.
+func (p Paginated) Page(i int) Menu {
+	pages := p.Pages()
+	i = min(max(i, 0), pages-1)
+
+	title := p.title
+	if pages > 1 {
+		title = fmt.Sprintf("%v (%v/%v)", p.title, i+1, pages)
+	}
+	m := Menu{title: title, body: p.body, onClose: p.onClose}
+	if p.search {
+		m = m.WithButton(p.nav.search, func(s Submitter) {
+			s.SendForm(p.searchForm(i))
+		})
+	}
+	for _, data := range p.btnData[i*p.pageSize : min((i+1)*p.pageSize, len(p.btnData))] {
+		m = m.WithButton(data.btn, data.onClick)
+	}
+	if i > 0 {
+		m = m.WithButton(p.nav.previous, func(s Submitter) {
+			s.SendForm(p.Page(i - 1))
+		})
+	}
+	if i < pages-1 {
+		m = m.WithButton(p.nav.next, func(s Submitter) {
+			s.SendForm(p.Page(i + 1))
+		})
+	}
+	if p.onBack != nil {
+		m = m.WithButton(p.nav.back, p.onBack)
+	}
+	return m
+}
+
+// searchForm returns the Custom form used to search the buttons of the menu. Closing it returns to the page
+// with the index passed.
+func (p Paginated) searchForm(page int) Custom {
+	f, query := Add(NewCustom(p.title), NewInput("Search", "", "Text to search for"))
+	return f.OnClose(func(s Submitter) {
+		s.SendForm(p.Page(page))
+	}).OnResponse(func(s Submitter, r Response) {
+		s.SendForm(p.Filter(query.Value(r)).Page(0))
+	})
+}
+
+// Filter returns a copy of the Paginated menu that only has the buttons with text containing the query
+// passed, ignoring case and formatting codes. The back button of the copy returns to the first page of the
+// unfiltered menu.
+func (p Paginated) Filter(query string) Paginated {
+	if p.unfiltered != nil {
+		return p.unfiltered.Filter(query)
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+	filtered := p
+	filtered.btnData = nil
+	filtered.title = fmt.Sprintf("%v: %v", p.title, query)
+	for _, data := range p.btnData {
+		if strings.Contains(strings.ToLower(text.Clean(data.btn.Text)), query) {
+			filtered.btnData = append(filtered.btnData, data)
+		}
+	}
+	filtered.onBack = func(s Submitter) {
+		s.SendForm(p.Page(0))
+	}
+	filtered.unfiltered = &p
+	return filtered
+}
+
+// MarshalJSON ...
+func (p Paginated) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Page(0))
+}
+
+// SubmitJSON submits a JSON value to the first page of the menu, containing the index of the button clicked.
+func (p Paginated) SubmitJSON(b []byte, submitter Submitter) error {
+	return p.Page(0).SubmitJSON(b, submitter)
+}
+
+func (Paginated) __() {}
@@ -0,0 +1,104 @@
+package eform
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/player/form"
+	"github.com/df-mc/dragonfly/server/world"
+	"golang.org/x/text/language"
+)
+
+// Player is a Submitter wrapping the *player.Player that a form was sent to. It is passed to the callbacks of
+// forms sent using Send or SendHandle. Like the *player.Player itself, it is only valid within the transaction
+// that the callback is called in.
+type Player struct {
+	*player.Player
+}
+
+// SendForm sends the Form passed to the player.
+func (p Player) SendForm(f Form) {
+	Send(p.Player, f)
+}
+
+// PlayerOf returns the *player.Player that the Submitter passed wraps, if any.
+func PlayerOf(s Submitter) (*player.Player, bool) {
+	switch s := s.(type) {
+	case Player:
+		return s.Player, true
+	case wrapper:
+		return PlayerOf(s.unwrap())
+	}
+	return nil, false
+}
+
+// submitter wraps a form.Submitter that is not a *player.Player.
+type submitter struct {
+	form.Submitter
+}
+
+// SendForm sends the Form passed to the submitter, translated to the fallback language of Translations.
+func (s submitter) SendForm(f Form) {
+	s.Submitter.SendForm(adapter{f: resolve(Translations().Localize(f, language.Und), s)})
+}
+
+// adapter implements form.Form for a Form.
+type adapter struct {
+	f Form
+}
+
+// Adapt returns a form.Form for the Form passed, so that it may be sent using the form system of dragonfly.
+// The Submitter passed to the callbacks of the form is a Player if the form was submitted by a player.
+// Forms computed when they are sent, such as Dynamic, are computed when Adapt is called, without a Submitter,
+// so Adapt must be called for every time the form is sent.
+// As the Submitter is not known when Adapt is called, the expiry callback of a Guarded form is only called if
+// the form is submitted after it expired, and not at its deadline.
+func Adapt(f Form) form.Form {
+	return adapter{f: resolve(f, nil)}
+}
+
+// MarshalJSON ...
+func (a adapter) MarshalJSON() ([]byte, error) {
+	return a.f.MarshalJSON()
+}
+
+// SubmitJSON submits the response to the Form. Responses rejected by a Guarded form are logged and otherwise
+// ignored, as dragonfly closes the session of a player if an error is returned.
+func (a adapter) SubmitJSON(b []byte, s form.Submitter, _ *world.Tx) error {
+	var err error
+	if p, ok := s.(*player.Player); ok {
+		err = a.f.SubmitJSON(b, Player{Player: p})
+	} else {
+		err = a.f.SubmitJSON(b, submitter{Submitter: s})
+	}
+	if errors.Is(err, ErrAnswered) || errors.Is(err, ErrExpired) {
+		if n, ok := s.(interface{ Name() string }); ok {
+			slog.Debug("ignored form response", "submitter", n.Name(), "err", err)
+		} else {
+			slog.Debug("ignored form response", "err", err)
+		}
+		return nil
+	}
+	return err
+}
+
+// Send sends the Form passed to the player, translated to the language of the player using Translations. It
+// must be called within the transaction of the player.
+func Send(p *player.Player, f Form) {
+	p.SendForm(adapter{f: resolve(Translations().Localize(f, p.Locale()), Player{Player: p})})
+}
+
+// SendHandle sends the Form passed to the player of the handle passed, such as those returned by
+// server.Server.PlayerByName, within a transaction of its world. False is returned if the handle did not
+// belong to a player or the player is no longer in a world.
+func SendHandle(h *world.EntityHandle, f Form) bool {
+	sent := false
+	h.ExecWorld(func(tx *world.Tx, e world.Entity) {
+		if p, ok := e.(*player.Player); ok {
+			Send(p, f)
+			sent = true
+		}
+	})
+	return sent
+}
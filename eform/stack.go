@@ -0,0 +1,180 @@
+package eform
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// Stack is a navigation stack of forms sent to a Submitter. Forms pushed to the Stack are sent to the
+// Submitter, and the callbacks of these forms are passed a Submitter that belongs to the Stack, so that
+// StackOf returns the same Stack within them.
+// Forms sent using SendForm of such a Submitter, for example to move to another page of a Paginated menu or
+// to re-prompt a Custom form, replace the current form on the Stack, so that returning to a form using Pop
+// restores the state it was last sent in.
+type Stack struct {
+	mu            sync.Mutex
+	s             Submitter
+	forms         []Form
+	returnOnClose bool
+}
+
+// StackOf returns the navigation Stack of the Submitter passed. If the Submitter was passed to the callback of
+// a form sent using a Stack, that Stack is returned. Otherwise, a new empty Stack is returned.
+func StackOf(s Submitter) *Stack {
+	for inner := s; ; {
+		if n, ok := inner.(stackSubmitter); ok {
+			return n.st
+		}
+		w, ok := inner.(wrapper)
+		if !ok {
+			return &Stack{s: s}
+		}
+		inner = w.unwrap()
+	}
+}
+
+// ReturnOnClose makes closing any form on the Stack return to the previous form, as if Pop was called,
+// instead of calling the close callback of the form. The close callback is still called if the form closed is
+// the only form on the Stack.
+func (st *Stack) ReturnOnClose() *Stack {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.returnOnClose = true
+	return st
+}
+
+// Push pushes the Form passed on top of the Stack and sends it to the Submitter.
+func (st *Stack) Push(f Form) {
+	st.mu.Lock()
+	st.forms = append(st.forms, f)
+	st.mu.Unlock()
+	st.send(f)
+}
+
+// Replace replaces the form on top of the Stack with the Form passed and sends it to the Submitter. If the
+// Stack is empty, the Form is pushed on top of it.
+func (st *Stack) Replace(f Form) {
+	st.mu.Lock()
+	if len(st.forms) == 0 {
+		st.forms = append(st.forms, f)
+	} else {
+		st.forms[len(st.forms)-1] = f
+	}
+	st.mu.Unlock()
+	st.send(f)
+}
+
+// Pop removes the form on top of the Stack and sends the previous form to the Submitter again. False is
+// returned if there was no previous form to return to.
+func (st *Stack) Pop() bool {
+	return st.PopN(1)
+}
+
+// PopN removes the n forms on top of the Stack and sends the form below them to the Submitter again, without
+// sending the forms in between. False is returned if there was no form n forms back to return to, in which
+// case the Stack is emptied. PopN does nothing and returns false if n is smaller than 1.
+func (st *Stack) PopN(n int) bool {
+	if n < 1 {
+		return false
+	}
+	st.mu.Lock()
+	if len(st.forms) <= n {
+		st.forms = nil
+		st.mu.Unlock()
+		return false
+	}
+	st.forms = st.forms[:len(st.forms)-n]
+	f := st.forms[len(st.forms)-1]
+	st.mu.Unlock()
+	st.send(f)
+	return true
+}
+
+// Len returns the number of forms on the Stack.
+func (st *Stack) Len() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return len(st.forms)
+}
+
+// send sends the Form passed to the Submitter of the Stack.
+func (st *Stack) send(f Form) {
+	st.mu.Lock()
+	s := st.s
+	st.mu.Unlock()
+	s.SendForm(stackForm{f: f, st: st})
+}
+
+// Push returns a Handler that pushes the Form passed on the navigation Stack of the Submitter.
+func Push(f Form) Handler {
+	return func(s Submitter) {
+		StackOf(s).Push(f)
+	}
+}
+
+// Replace returns a Handler that replaces the current form on the navigation Stack of the Submitter with the
+// Form passed.
+func Replace(f Form) Handler {
+	return func(s Submitter) {
+		StackOf(s).Replace(f)
+	}
+}
+
+// Back is a Handler that returns the Submitter to the previous form on its navigation Stack. It may be used
+// as the callback of a back button or as the close callback of a form.
+func Back(s Submitter) {
+	StackOf(s).Pop()
+}
+
+// stackSubmitter is the Submitter passed to the callbacks of forms sent using a Stack.
+type stackSubmitter struct {
+	Submitter
+	st *Stack
+}
+
+// SendForm replaces the current form on the Stack with the Form passed.
+func (s stackSubmitter) SendForm(f Form) {
+	if sf, ok := f.(stackForm); ok && sf.st == s.st {
+		f = sf.f
+	}
+	s.st.Replace(f)
+}
+
+// unwrap ...
+func (s stackSubmitter) unwrap() Submitter {
+	return s.Submitter
+}
+
+// stackForm is a Form sent using a Stack.
+type stackForm struct {
+	f  Form
+	st *Stack
+}
+
+// MarshalJSON ...
+func (f stackForm) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.f)
+}
+
+// SubmitJSON ...
+func (f stackForm) SubmitJSON(b []byte, submitter Submitter) error {
+	f.st.mu.Lock()
+	// The Submitter passed may only be valid while the form is being submitted, such as a Player, so forms
+	// sent from now on are sent using it.
+	f.st.s = submitter
+	returnOnClose := f.st.returnOnClose && len(f.st.forms) > 1
+	f.st.mu.Unlock()
+	if b == nil && returnOnClose {
+		f.st.Pop()
+		return nil
+	}
+	return f.f.SubmitJSON(b, stackSubmitter{Submitter: submitter, st: f.st})
+}
+
+// resolve ...
+func (f stackForm) resolve(s Submitter) Form {
+	f.f = resolve(f.f, s)
+	return f
+}
+
+func (stackForm) __() {}
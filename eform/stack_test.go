@@ -0,0 +1,30 @@
+package eform
+
+import (
+	"testing"
+)
+
+func TestStackPopN(t *testing.T) {
+	s := &recordSubmitter{}
+	st := StackOf(s)
+	for _, title := range []string{"a", "b", "c", "d"} {
+		st.Push(NewMenu(title))
+	}
+	s.forms = nil
+
+	if st.PopN(0) || st.PopN(-2) || st.Len() != 4 || len(s.forms) != 0 {
+		t.Fatalf("expected popping less than one form to do nothing, got %v forms left and %v sent", st.Len(), len(s.forms))
+	}
+	if !st.PopN(2) {
+		t.Fatal("expected to return to a form two forms back")
+	}
+	if len(s.forms) != 1 || s.forms[0].(stackForm).f.(Menu).Title() != "b" {
+		t.Fatalf("expected only form b to be sent, got %v", s.forms)
+	}
+	if st.Len() != 2 {
+		t.Fatalf("expected 2 forms left on the stack, got %v", st.Len())
+	}
+	if st.PopN(2) || st.Len() != 0 || len(s.forms) != 1 {
+		t.Fatalf("expected the stack to be emptied without sending a form, got %v forms left", st.Len())
+	}
+}
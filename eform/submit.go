@@ -6,6 +6,12 @@ type Submitter interface {
 	SendForm(form Form)
 }
 
+// wrapper is implemented by the Submitters passed to the callbacks of forms that wrap another form, such as
+// those sent using a Stack. unwrap returns the Submitter wrapped.
+type wrapper interface {
+	unwrap() Submitter
+}
+
 // Handler is closure used in form closing, button click
 type Handler func(Submitter)
 
@@ -14,3 +20,19 @@ func (c Handler) Call(s Submitter) {
 		c(s)
 	}
 }
+
+// resolver is implemented by forms that depend on the Submitter they are sent to, such as Dynamic, of which
+// the content is computed when it is sent, or Guarded, which calls its expiry callback with the Submitter. It
+// is also implemented by forms wrapping other forms.
+type resolver interface {
+	// resolve returns the form sent to the Submitter passed, which may be nil if it is not known.
+	resolve(s Submitter) Form
+}
+
+// resolve returns the form that is sent to the Submitter passed for the Form passed.
+func resolve(f Form, s Submitter) Form {
+	if r, ok := f.(resolver); ok {
+		return r.resolve(s)
+	}
+	return f
+}
@@ -0,0 +1,286 @@
+package eform
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// The runes used to encode translations in strings returned by T. They are from the private use area of
+// Unicode, so that they do not collide with text displayed to players.
+const (
+	translationStart = '\uE000'
+	translationSep   = '\uE001'
+	translationEnd   = '\uE002'
+)
+
+// T returns a string referring to the translation with the key passed, which may be used in place of any text
+// of a form, such as titles, bodies, button text and element text. When the form is sent to a player, the
+// string is replaced with the translation in the language of the player, with occurrences of {0}, {1}, ... in
+// the translation replaced with the params passed, formatted using fmt.Sprint.
+// The params may themselves be strings returned by T.
+func T(key string, params ...any) string {
+	var sb strings.Builder
+	sb.WriteRune(translationStart)
+	sb.WriteString(key)
+	for _, param := range params {
+		sb.WriteRune(translationSep)
+		sb.WriteString(fmt.Sprint(param))
+	}
+	sb.WriteRune(translationEnd)
+	return sb.String()
+}
+
+// Translator holds translations for any number of languages. Translations are looked up in the language that
+// best matches the one requested, followed by the fallback language of the Translator.
+type Translator struct {
+	mu       sync.RWMutex
+	fallback language.Tag
+	tags     []language.Tag
+	langs    map[language.Tag]map[string]string
+	matcher  language.Matcher
+}
+
+// NewTranslator returns a new Translator with no translations, using the fallback language passed for keys
+// that are not translated in the language requested.
+func NewTranslator(fallback language.Tag) *Translator {
+	return &Translator{fallback: fallback, langs: map[language.Tag]map[string]string{}}
+}
+
+var translations = func() *Translator {
+	t := NewTranslator(language.AmericanEnglish)
+	t.Add(language.AmericanEnglish, validateTranslations)
+	return t
+}()
+
+// Translations returns the Translator used to translate forms sent to players using Send.
+func Translations() *Translator {
+	return translations
+}
+
+// Add adds the translations passed, mapping keys to translated text, to the language passed. Existing
+// translations of the same keys are overwritten.
+func (t *Translator) Add(tag language.Tag, translations map[string]string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	lang, ok := t.langs[tag]
+	if !ok {
+		lang = make(map[string]string, len(translations))
+		t.langs[tag] = lang
+		t.tags = append(t.tags, tag)
+		t.matcher = language.NewMatcher(t.tags)
+	}
+	for k, v := range translations {
+		lang[k] = v
+	}
+}
+
+// LoadFile loads the translations of the language file at the path passed. The language is determined by the
+// name of the file, such as en_US.lang or de-DE.lang. Every line of the file holds a translation in the form
+// key=text. Empty lines and lines starting with # are ignored.
+func (t *Translator) LoadFile(path string) error {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	tag, err := language.Parse(strings.ReplaceAll(name, "_", "-"))
+	if err != nil {
+		return fmt.Errorf("language file %v: %w", path, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lang := make(map[string]string)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; sc.Scan(); line++ {
+		s := strings.TrimSpace(sc.Text())
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+		k, v, ok := strings.Cut(s, "=")
+		if !ok {
+			return fmt.Errorf("language file %v: line %v: expected key=text", path, line)
+		}
+		lang[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("language file %v: %w", path, err)
+	}
+	t.Add(tag, lang)
+	return nil
+}
+
+// LoadDir loads all language files with the .lang extension in the directory passed using LoadFile.
+func (t *Translator) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.lang"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if err := t.LoadFile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Translate returns the translation of the key passed in the language passed, with the params passed
+// substituted. If the key is not translated in the language or the fallback language, the key itself is
+// returned.
+func (t *Translator) Translate(tag language.Tag, key string, params ...any) string {
+	t.mu.RLock()
+	text, ok := t.lookup(tag, key)
+	t.mu.RUnlock()
+	if !ok {
+		text = key
+	}
+	if len(params) == 0 {
+		return text
+	}
+	oldNew := make([]string, 0, len(params)*2)
+	for i, param := range params {
+		oldNew = append(oldNew, "{"+strconv.Itoa(i)+"}", fmt.Sprint(param))
+	}
+	return strings.NewReplacer(oldNew...).Replace(text)
+}
+
+// lookup looks up the translation of the key passed in the language that best matches the one passed,
+// followed by the fallback language.
+func (t *Translator) lookup(tag language.Tag, key string) (string, bool) {
+	if t.matcher != nil && tag != language.Und {
+		if _, i, conf := t.matcher.Match(tag); conf != language.No {
+			if text, ok := t.langs[t.tags[i]][key]; ok {
+				return text, true
+			}
+		}
+	}
+	text, ok := t.langs[t.fallback][key]
+	return text, ok
+}
+
+// translateString replaces all strings returned by T in the string passed with their translations.
+func (t *Translator) translateString(tag language.Tag, s string) string {
+	if !strings.ContainsRune(s, translationStart) {
+		return s
+	}
+	var sb strings.Builder
+	for {
+		start := strings.IndexRune(s, translationStart)
+		if start == -1 {
+			break
+		}
+		sb.WriteString(s[:start])
+		rest := s[start+len(string(translationStart)):]
+		parts, n, ok := splitTranslation(rest)
+		if !ok {
+			// Unterminated translation: it is written as is.
+			sb.WriteString(s[start:])
+			return sb.String()
+		}
+		params := make([]any, len(parts)-1)
+		for i, param := range parts[1:] {
+			params[i] = t.translateString(tag, param)
+		}
+		sb.WriteString(t.Translate(tag, parts[0], params...))
+		s = rest[n:]
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
+
+// splitTranslation splits the content of a translation, following the start rune, into its key and params.
+// The number of bytes consumed, including the end rune, is returned. Translations nested in params are kept
+// intact.
+func splitTranslation(s string) (parts []string, n int, ok bool) {
+	depth, last := 0, 0
+	for i, r := range s {
+		switch r {
+		case translationStart:
+			depth++
+		case translationEnd:
+			if depth == 0 {
+				return append(parts, s[last:i]), i + len(string(translationEnd)), true
+			}
+			depth--
+		case translationSep:
+			if depth == 0 {
+				parts = append(parts, s[last:i])
+				last = i + len(string(translationSep))
+			}
+		}
+	}
+	return nil, 0, false
+}
+
+// translateValue replaces the strings returned by T in all strings held by the decoded JSON value passed.
+func (t *Translator) translateValue(tag language.Tag, v any) any {
+	switch v := v.(type) {
+	case string:
+		return t.translateString(tag, v)
+	case []any:
+		for i, e := range v {
+			v[i] = t.translateValue(tag, e)
+		}
+	case map[string]any:
+		for k, e := range v {
+			v[k] = t.translateValue(tag, e)
+		}
+	}
+	return v
+}
+
+// Localize returns a Form that is displayed in the language passed, with all strings returned by T in the
+// Form passed replaced with their translations. Submitting the Form returned submits the Form passed.
+func (t *Translator) Localize(f Form, tag language.Tag) Form {
+	return localized{f: f, t: t, tag: tag}
+}
+
+// localized is a Form translated to a language.
+type localized struct {
+	f   Form
+	t   *Translator
+	tag language.Tag
+}
+
+// MarshalJSON ...
+func (l localized) MarshalJSON() ([]byte, error) {
+	b, err := l.f.MarshalJSON()
+	if err != nil || !bytes.ContainsRune(b, translationStart) {
+		return b, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(l.t.translateValue(l.tag, v))
+}
+
+// SubmitJSON ...
+func (l localized) SubmitJSON(b []byte, submitter Submitter) error {
+	return l.f.SubmitJSON(b, submitter)
+}
+
+// resolve ...
+func (l localized) resolve(s Submitter) Form {
+	l.f = resolve(l.f, s)
+	return l
+}
+
+func (localized) __() {}
+
+// LocaleOf returns the language of the Submitter passed, which is the language selected in the settings of the
+// client if the Submitter is a player. False is returned if the language of the Submitter is not known.
+func LocaleOf(s Submitter) (language.Tag, bool) {
+	if p, ok := PlayerOf(s); ok {
+		return p.Locale(), true
+	}
+	return language.Und, false
+}
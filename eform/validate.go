@@ -0,0 +1,51 @@
+package eform
+
+import (
+	"errors"
+	"strconv"
+	"unicode/utf8"
+)
+
+// validateTranslations are the translations of the errors returned by the validators in the fallback
+// language of Translations. They are displayed in the language of the player when a form is re-prompted, and
+// may be overwritten using Translator.Add.
+var validateTranslations = map[string]string{
+	"eform.validate.length": "Must be {0}-{1} characters long",
+	"eform.validate.int":    "Must be a whole number",
+	"eform.validate.range":  "Must be between {0} and {1}",
+}
+
+// ValidateLength returns a validator for an Input that accepts text with a length between min and max
+// characters, inclusive.
+func ValidateLength(min, max int) func(string) error {
+	return func(s string) error {
+		if n := utf8.RuneCountInString(s); n < min || n > max {
+			return errors.New(T("eform.validate.length", min, max))
+		}
+		return nil
+	}
+}
+
+// ValidateInt returns a validator for an Input that accepts whole numbers between min and max, inclusive.
+func ValidateInt(min, max int) func(string) error {
+	return func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return errors.New(T("eform.validate.int"))
+		}
+		if n < min || n > max {
+			return errors.New(T("eform.validate.range", min, max))
+		}
+		return nil
+	}
+}
+
+// ValidateRange returns a validator for a Slider that accepts values between min and max, inclusive.
+func ValidateRange(min, max float64) func(float64) error {
+	return func(f float64) error {
+		if f < min || f > max {
+			return errors.New(T("eform.validate.range", min, max))
+		}
+		return nil
+	}
+}
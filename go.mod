@@ -7,18 +7,21 @@ toolchain go1.23.6
 require (
 	github.com/dave/jennifer v1.5.1
 	github.com/df-mc/dragonfly v0.10.1
+	github.com/df-mc/goleveldb v1.1.9
 	github.com/go-gl/mathgl v1.2.0
 	github.com/google/uuid v1.6.0
+	github.com/mattn/go-sqlite3 v1.14.24
 	github.com/pelletier/go-toml v1.9.5
 	github.com/sandertv/gophertunnel v1.43.1
 	go.uber.org/atomic v1.11.0
 	golang.org/x/exp v0.0.0-20250207012021-f9890c6ad9f3
+	golang.org/x/text v0.22.0
+	golang.org/x/tools v0.30.0
 )
 
 require (
 	github.com/brentp/intintmap v0.0.0-20190211203843-30dc0ade9af9 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
-	github.com/df-mc/goleveldb v1.1.9 // indirect
 	github.com/df-mc/worldupgrader v1.0.18 // indirect
 	github.com/go-jose/go-jose/v4 v4.0.4 // indirect
 	github.com/golang/snappy v0.0.4 // indirect
@@ -28,7 +31,7 @@ require (
 	github.com/segmentio/fasthash v1.0.3 // indirect
 	golang.org/x/crypto v0.33.0 // indirect
 	golang.org/x/mod v0.23.0 // indirect
-	golang.org/x/net v0.34.0 // indirect
+	golang.org/x/net v0.35.0 // indirect
 	golang.org/x/oauth2 v0.26.0 // indirect
-	golang.org/x/text v0.22.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
 )
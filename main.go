@@ -33,11 +33,10 @@ func (m myQuitHandler) HandleQuit(p *player.Player) {
 
 func main() {
 	log := bootstrap.NewLogger()
+	mhandler.Global().Register(myQuitHandler{})
 	bootstrap.Default(log, nil, func(p *player.Player) {
-		h := mhandler.New()
-		p.Handle(h)
-		unreg := h.Register(myBlockBreakHandler{})
+		h := mhandler.Attach(p)
+		unreg := h.Register(myBlockBreakHandler{}, mhandler.WithPriority(mhandler.PriorityHigh))
 		h.Register(myChatHandler{unreg: unreg})
-		h.Register(myQuitHandler{})
 	}, nil)()
 }
@@ -1,6 +1,11 @@
+// Code generated by tools/gen_handlers.go from github.com/df-mc/dragonfly@v0.10.1. DO NOT EDIT.
+
 package mhandler
 
 import (
+	"net"
+	"time"
+
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/cmd"
 	"github.com/df-mc/dragonfly/server/event"
@@ -10,8 +15,6 @@ import (
 	"github.com/df-mc/dragonfly/server/session"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
-	"net"
-	"time"
 )
 
 type MoveHandler interface {
@@ -221,596 +224,696 @@ type DiagnosticsHandler interface {
 }
 
 func (h *MultipleHandler) HandleMove(ctx *event.Context[*player.Player], newPos mgl64.Vec3, newRot cube.Rotation) {
-	for _, hdr := range h._MoveHandler {
-		if hdr, ok := hdr.(MoveHandler); ok {
-			hdr.HandleMove(ctx, newPos, newRot)
+	for _, ent := range h.merged()._MoveHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleMove(ctx, newPos, newRot)
 	}
 }
 func (h *MultipleHandler) HandleJump(p *player.Player) {
-	for _, hdr := range h._JumpHandler {
-		if hdr, ok := hdr.(JumpHandler); ok {
-			hdr.HandleJump(p)
-		}
+	for _, ent := range h.merged()._JumpHandler {
+		ent.hdr.HandleJump(p)
 	}
 }
 func (h *MultipleHandler) HandleTeleport(ctx *event.Context[*player.Player], pos mgl64.Vec3) {
-	for _, hdr := range h._TeleportHandler {
-		if hdr, ok := hdr.(TeleportHandler); ok {
-			hdr.HandleTeleport(ctx, pos)
+	for _, ent := range h.merged()._TeleportHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleTeleport(ctx, pos)
 	}
 }
 func (h *MultipleHandler) HandleChangeWorld(p *player.Player, before, after *world.World) {
-	for _, hdr := range h._ChangeWorldHandler {
-		if hdr, ok := hdr.(ChangeWorldHandler); ok {
-			hdr.HandleChangeWorld(p, before, after)
-		}
+	for _, ent := range h.merged()._ChangeWorldHandler {
+		ent.hdr.HandleChangeWorld(p, before, after)
 	}
 }
 func (h *MultipleHandler) HandleToggleSprint(ctx *event.Context[*player.Player], after bool) {
-	for _, hdr := range h._ToggleSprintHandler {
-		if hdr, ok := hdr.(ToggleSprintHandler); ok {
-			hdr.HandleToggleSprint(ctx, after)
+	for _, ent := range h.merged()._ToggleSprintHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleToggleSprint(ctx, after)
 	}
 }
 func (h *MultipleHandler) HandleToggleSneak(ctx *event.Context[*player.Player], after bool) {
-	for _, hdr := range h._ToggleSneakHandler {
-		if hdr, ok := hdr.(ToggleSneakHandler); ok {
-			hdr.HandleToggleSneak(ctx, after)
+	for _, ent := range h.merged()._ToggleSneakHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleToggleSneak(ctx, after)
 	}
 }
 func (h *MultipleHandler) HandleChat(ctx *event.Context[*player.Player], message *string) {
-	for _, hdr := range h._ChatHandler {
-		if hdr, ok := hdr.(ChatHandler); ok {
-			hdr.HandleChat(ctx, message)
+	for _, ent := range h.merged()._ChatHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleChat(ctx, message)
 	}
 }
 func (h *MultipleHandler) HandleFoodLoss(ctx *event.Context[*player.Player], from int, to *int) {
-	for _, hdr := range h._FoodLossHandler {
-		if hdr, ok := hdr.(FoodLossHandler); ok {
-			hdr.HandleFoodLoss(ctx, from, to)
+	for _, ent := range h.merged()._FoodLossHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleFoodLoss(ctx, from, to)
 	}
 }
 func (h *MultipleHandler) HandleHeal(ctx *event.Context[*player.Player], health *float64, src world.HealingSource) {
-	for _, hdr := range h._HealHandler {
-		if hdr, ok := hdr.(HealHandler); ok {
-			hdr.HandleHeal(ctx, health, src)
+	for _, ent := range h.merged()._HealHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleHeal(ctx, health, src)
 	}
 }
 func (h *MultipleHandler) HandleHurt(ctx *event.Context[*player.Player], damage *float64, immune bool, attackImmunity *time.Duration, src world.DamageSource) {
-	for _, hdr := range h._HurtHandler {
-		if hdr, ok := hdr.(HurtHandler); ok {
-			hdr.HandleHurt(ctx, damage, immune, attackImmunity, src)
+	for _, ent := range h.merged()._HurtHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleHurt(ctx, damage, immune, attackImmunity, src)
 	}
 }
 func (h *MultipleHandler) HandleDeath(p *player.Player, src world.DamageSource, keepInv *bool) {
-	for _, hdr := range h._DeathHandler {
-		if hdr, ok := hdr.(DeathHandler); ok {
-			hdr.HandleDeath(p, src, keepInv)
-		}
+	for _, ent := range h.merged()._DeathHandler {
+		ent.hdr.HandleDeath(p, src, keepInv)
 	}
 }
 func (h *MultipleHandler) HandleRespawn(p *player.Player, pos *mgl64.Vec3, w **world.World) {
-	for _, hdr := range h._RespawnHandler {
-		if hdr, ok := hdr.(RespawnHandler); ok {
-			hdr.HandleRespawn(p, pos, w)
-		}
+	for _, ent := range h.merged()._RespawnHandler {
+		ent.hdr.HandleRespawn(p, pos, w)
 	}
 }
 func (h *MultipleHandler) HandleSkinChange(ctx *event.Context[*player.Player], skin *skin.Skin) {
-	for _, hdr := range h._SkinChangeHandler {
-		if hdr, ok := hdr.(SkinChangeHandler); ok {
-			hdr.HandleSkinChange(ctx, skin)
+	for _, ent := range h.merged()._SkinChangeHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleSkinChange(ctx, skin)
 	}
 }
 func (h *MultipleHandler) HandleFireExtinguish(ctx *event.Context[*player.Player], pos cube.Pos) {
-	for _, hdr := range h._FireExtinguishHandler {
-		if hdr, ok := hdr.(FireExtinguishHandler); ok {
-			hdr.HandleFireExtinguish(ctx, pos)
+	for _, ent := range h.merged()._FireExtinguishHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleFireExtinguish(ctx, pos)
 	}
 }
 func (h *MultipleHandler) HandleStartBreak(ctx *event.Context[*player.Player], pos cube.Pos) {
-	for _, hdr := range h._StartBreakHandler {
-		if hdr, ok := hdr.(StartBreakHandler); ok {
-			hdr.HandleStartBreak(ctx, pos)
+	for _, ent := range h.merged()._StartBreakHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleStartBreak(ctx, pos)
 	}
 }
 func (h *MultipleHandler) HandleBlockBreak(ctx *event.Context[*player.Player], pos cube.Pos, drops *[]item.Stack, xp *int) {
-	for _, hdr := range h._BlockBreakHandler {
-		if hdr, ok := hdr.(BlockBreakHandler); ok {
-			hdr.HandleBlockBreak(ctx, pos, drops, xp)
+	for _, ent := range h.merged()._BlockBreakHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleBlockBreak(ctx, pos, drops, xp)
 	}
 }
 func (h *MultipleHandler) HandleBlockPlace(ctx *event.Context[*player.Player], pos cube.Pos, b world.Block) {
-	for _, hdr := range h._BlockPlaceHandler {
-		if hdr, ok := hdr.(BlockPlaceHandler); ok {
-			hdr.HandleBlockPlace(ctx, pos, b)
+	for _, ent := range h.merged()._BlockPlaceHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleBlockPlace(ctx, pos, b)
 	}
 }
 func (h *MultipleHandler) HandleBlockPick(ctx *event.Context[*player.Player], pos cube.Pos, b world.Block) {
-	for _, hdr := range h._BlockPickHandler {
-		if hdr, ok := hdr.(BlockPickHandler); ok {
-			hdr.HandleBlockPick(ctx, pos, b)
+	for _, ent := range h.merged()._BlockPickHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleBlockPick(ctx, pos, b)
 	}
 }
 func (h *MultipleHandler) HandleItemUse(ctx *event.Context[*player.Player]) {
-	for _, hdr := range h._ItemUseHandler {
-		if hdr, ok := hdr.(ItemUseHandler); ok {
-			hdr.HandleItemUse(ctx)
+	for _, ent := range h.merged()._ItemUseHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleItemUse(ctx)
 	}
 }
 func (h *MultipleHandler) HandleItemUseOnBlock(ctx *event.Context[*player.Player], pos cube.Pos, face cube.Face, clickPos mgl64.Vec3) {
-	for _, hdr := range h._ItemUseOnBlockHandler {
-		if hdr, ok := hdr.(ItemUseOnBlockHandler); ok {
-			hdr.HandleItemUseOnBlock(ctx, pos, face, clickPos)
+	for _, ent := range h.merged()._ItemUseOnBlockHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleItemUseOnBlock(ctx, pos, face, clickPos)
 	}
 }
 func (h *MultipleHandler) HandleItemUseOnEntity(ctx *event.Context[*player.Player], e world.Entity) {
-	for _, hdr := range h._ItemUseOnEntityHandler {
-		if hdr, ok := hdr.(ItemUseOnEntityHandler); ok {
-			hdr.HandleItemUseOnEntity(ctx, e)
+	for _, ent := range h.merged()._ItemUseOnEntityHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleItemUseOnEntity(ctx, e)
 	}
 }
 func (h *MultipleHandler) HandleItemRelease(ctx *event.Context[*player.Player], item item.Stack, dur time.Duration) {
-	for _, hdr := range h._ItemReleaseHandler {
-		if hdr, ok := hdr.(ItemReleaseHandler); ok {
-			hdr.HandleItemRelease(ctx, item, dur)
+	for _, ent := range h.merged()._ItemReleaseHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleItemRelease(ctx, item, dur)
 	}
 }
 func (h *MultipleHandler) HandleItemConsume(ctx *event.Context[*player.Player], item item.Stack) {
-	for _, hdr := range h._ItemConsumeHandler {
-		if hdr, ok := hdr.(ItemConsumeHandler); ok {
-			hdr.HandleItemConsume(ctx, item)
+	for _, ent := range h.merged()._ItemConsumeHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleItemConsume(ctx, item)
 	}
 }
 func (h *MultipleHandler) HandleAttackEntity(ctx *event.Context[*player.Player], e world.Entity, force, height *float64, critical *bool) {
-	for _, hdr := range h._AttackEntityHandler {
-		if hdr, ok := hdr.(AttackEntityHandler); ok {
-			hdr.HandleAttackEntity(ctx, e, force, height, critical)
+	for _, ent := range h.merged()._AttackEntityHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleAttackEntity(ctx, e, force, height, critical)
 	}
 }
 func (h *MultipleHandler) HandleExperienceGain(ctx *event.Context[*player.Player], amount *int) {
-	for _, hdr := range h._ExperienceGainHandler {
-		if hdr, ok := hdr.(ExperienceGainHandler); ok {
-			hdr.HandleExperienceGain(ctx, amount)
+	for _, ent := range h.merged()._ExperienceGainHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleExperienceGain(ctx, amount)
 	}
 }
 func (h *MultipleHandler) HandlePunchAir(ctx *event.Context[*player.Player]) {
-	for _, hdr := range h._PunchAirHandler {
-		if hdr, ok := hdr.(PunchAirHandler); ok {
-			hdr.HandlePunchAir(ctx)
+	for _, ent := range h.merged()._PunchAirHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandlePunchAir(ctx)
 	}
 }
 func (h *MultipleHandler) HandleSignEdit(ctx *event.Context[*player.Player], pos cube.Pos, frontSide bool, oldText, newText string) {
-	for _, hdr := range h._SignEditHandler {
-		if hdr, ok := hdr.(SignEditHandler); ok {
-			hdr.HandleSignEdit(ctx, pos, frontSide, oldText, newText)
+	for _, ent := range h.merged()._SignEditHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleSignEdit(ctx, pos, frontSide, oldText, newText)
 	}
 }
 func (h *MultipleHandler) HandleLecternPageTurn(ctx *event.Context[*player.Player], pos cube.Pos, oldPage int, newPage *int) {
-	for _, hdr := range h._LecternPageTurnHandler {
-		if hdr, ok := hdr.(LecternPageTurnHandler); ok {
-			hdr.HandleLecternPageTurn(ctx, pos, oldPage, newPage)
+	for _, ent := range h.merged()._LecternPageTurnHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleLecternPageTurn(ctx, pos, oldPage, newPage)
 	}
 }
 func (h *MultipleHandler) HandleItemDamage(ctx *event.Context[*player.Player], i item.Stack, damage int) {
-	for _, hdr := range h._ItemDamageHandler {
-		if hdr, ok := hdr.(ItemDamageHandler); ok {
-			hdr.HandleItemDamage(ctx, i, damage)
+	for _, ent := range h.merged()._ItemDamageHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleItemDamage(ctx, i, damage)
 	}
 }
 func (h *MultipleHandler) HandleItemPickup(ctx *event.Context[*player.Player], i *item.Stack) {
-	for _, hdr := range h._ItemPickupHandler {
-		if hdr, ok := hdr.(ItemPickupHandler); ok {
-			hdr.HandleItemPickup(ctx, i)
+	for _, ent := range h.merged()._ItemPickupHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleItemPickup(ctx, i)
 	}
 }
 func (h *MultipleHandler) HandleHeldSlotChange(ctx *event.Context[*player.Player], from, to int) {
-	for _, hdr := range h._HeldSlotChangeHandler {
-		if hdr, ok := hdr.(HeldSlotChangeHandler); ok {
-			hdr.HandleHeldSlotChange(ctx, from, to)
+	for _, ent := range h.merged()._HeldSlotChangeHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleHeldSlotChange(ctx, from, to)
 	}
 }
 func (h *MultipleHandler) HandleItemDrop(ctx *event.Context[*player.Player], s item.Stack) {
-	for _, hdr := range h._ItemDropHandler {
-		if hdr, ok := hdr.(ItemDropHandler); ok {
-			hdr.HandleItemDrop(ctx, s)
+	for _, ent := range h.merged()._ItemDropHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleItemDrop(ctx, s)
 	}
 }
 func (h *MultipleHandler) HandleTransfer(ctx *event.Context[*player.Player], addr *net.UDPAddr) {
-	for _, hdr := range h._TransferHandler {
-		if hdr, ok := hdr.(TransferHandler); ok {
-			hdr.HandleTransfer(ctx, addr)
+	for _, ent := range h.merged()._TransferHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleTransfer(ctx, addr)
 	}
 }
 func (h *MultipleHandler) HandleCommandExecution(ctx *event.Context[*player.Player], command cmd.Command, args []string) {
-	for _, hdr := range h._CommandExecutionHandler {
-		if hdr, ok := hdr.(CommandExecutionHandler); ok {
-			hdr.HandleCommandExecution(ctx, command, args)
+	for _, ent := range h.merged()._CommandExecutionHandler {
+		if ent.skip(ctx) {
+			continue
 		}
+		ent.hdr.HandleCommandExecution(ctx, command, args)
 	}
 }
 func (h *MultipleHandler) HandleQuit(p *player.Player) {
-	for _, hdr := range h._QuitHandler {
-		if hdr, ok := hdr.(QuitHandler); ok {
-			hdr.HandleQuit(p)
-		}
+	for _, ent := range h.merged()._QuitHandler {
+		ent.hdr.HandleQuit(p)
 	}
 }
 func (h *MultipleHandler) HandleDiagnostics(p *player.Player, d session.Diagnostics) {
-	for _, hdr := range h._DiagnosticsHandler {
-		if hdr, ok := hdr.(DiagnosticsHandler); ok {
-			hdr.HandleDiagnostics(p, d)
-		}
+	for _, ent := range h.merged()._DiagnosticsHandler {
+		ent.hdr.HandleDiagnostics(p, d)
 	}
 }
 
+type multipleHandlerTable struct {
+	_MoveHandler             []*entry[MoveHandler]
+	_JumpHandler             []*entry[JumpHandler]
+	_TeleportHandler         []*entry[TeleportHandler]
+	_ChangeWorldHandler      []*entry[ChangeWorldHandler]
+	_ToggleSprintHandler     []*entry[ToggleSprintHandler]
+	_ToggleSneakHandler      []*entry[ToggleSneakHandler]
+	_ChatHandler             []*entry[ChatHandler]
+	_FoodLossHandler         []*entry[FoodLossHandler]
+	_HealHandler             []*entry[HealHandler]
+	_HurtHandler             []*entry[HurtHandler]
+	_DeathHandler            []*entry[DeathHandler]
+	_RespawnHandler          []*entry[RespawnHandler]
+	_SkinChangeHandler       []*entry[SkinChangeHandler]
+	_FireExtinguishHandler   []*entry[FireExtinguishHandler]
+	_StartBreakHandler       []*entry[StartBreakHandler]
+	_BlockBreakHandler       []*entry[BlockBreakHandler]
+	_BlockPlaceHandler       []*entry[BlockPlaceHandler]
+	_BlockPickHandler        []*entry[BlockPickHandler]
+	_ItemUseHandler          []*entry[ItemUseHandler]
+	_ItemUseOnBlockHandler   []*entry[ItemUseOnBlockHandler]
+	_ItemUseOnEntityHandler  []*entry[ItemUseOnEntityHandler]
+	_ItemReleaseHandler      []*entry[ItemReleaseHandler]
+	_ItemConsumeHandler      []*entry[ItemConsumeHandler]
+	_AttackEntityHandler     []*entry[AttackEntityHandler]
+	_ExperienceGainHandler   []*entry[ExperienceGainHandler]
+	_PunchAirHandler         []*entry[PunchAirHandler]
+	_SignEditHandler         []*entry[SignEditHandler]
+	_LecternPageTurnHandler  []*entry[LecternPageTurnHandler]
+	_ItemDamageHandler       []*entry[ItemDamageHandler]
+	_ItemPickupHandler       []*entry[ItemPickupHandler]
+	_HeldSlotChangeHandler   []*entry[HeldSlotChangeHandler]
+	_ItemDropHandler         []*entry[ItemDropHandler]
+	_TransferHandler         []*entry[TransferHandler]
+	_CommandExecutionHandler []*entry[CommandExecutionHandler]
+	_QuitHandler             []*entry[QuitHandler]
+	_DiagnosticsHandler      []*entry[DiagnosticsHandler]
+}
 type MultipleHandler struct {
-	_MoveHandler             []MoveHandler
-	_JumpHandler             []JumpHandler
-	_TeleportHandler         []TeleportHandler
-	_ChangeWorldHandler      []ChangeWorldHandler
-	_ToggleSprintHandler     []ToggleSprintHandler
-	_ToggleSneakHandler      []ToggleSneakHandler
-	_ChatHandler             []ChatHandler
-	_FoodLossHandler         []FoodLossHandler
-	_HealHandler             []HealHandler
-	_HurtHandler             []HurtHandler
-	_DeathHandler            []DeathHandler
-	_RespawnHandler          []RespawnHandler
-	_SkinChangeHandler       []SkinChangeHandler
-	_FireExtinguishHandler   []FireExtinguishHandler
-	_StartBreakHandler       []StartBreakHandler
-	_BlockBreakHandler       []BlockBreakHandler
-	_BlockPlaceHandler       []BlockPlaceHandler
-	_BlockPickHandler        []BlockPickHandler
-	_ItemUseHandler          []ItemUseHandler
-	_ItemUseOnBlockHandler   []ItemUseOnBlockHandler
-	_ItemUseOnEntityHandler  []ItemUseOnEntityHandler
-	_ItemReleaseHandler      []ItemReleaseHandler
-	_ItemConsumeHandler      []ItemConsumeHandler
-	_AttackEntityHandler     []AttackEntityHandler
-	_ExperienceGainHandler   []ExperienceGainHandler
-	_PunchAirHandler         []PunchAirHandler
-	_SignEditHandler         []SignEditHandler
-	_LecternPageTurnHandler  []LecternPageTurnHandler
-	_ItemDamageHandler       []ItemDamageHandler
-	_ItemPickupHandler       []ItemPickupHandler
-	_HeldSlotChangeHandler   []HeldSlotChangeHandler
-	_ItemDropHandler         []ItemDropHandler
-	_TransferHandler         []TransferHandler
-	_CommandExecutionHandler []CommandExecutionHandler
-	_QuitHandler             []QuitHandler
-	_DiagnosticsHandler      []DiagnosticsHandler
+	handlers[multipleHandlerTable]
 }
 
-func (h *MultipleHandler) Register(hdr any) func() {
-	reg := false
-	var funcs []func()
+// merged returns the handlers registered to h merged with those of its parent, sorted by priority.
+func (h *MultipleHandler) merged() *multipleHandlerTable {
+	return h.mergedWith(func(parent, own *multipleHandlerTable) *multipleHandlerTable {
+		return &multipleHandlerTable{
+			_AttackEntityHandler:     merge(parent._AttackEntityHandler, own._AttackEntityHandler),
+			_BlockBreakHandler:       merge(parent._BlockBreakHandler, own._BlockBreakHandler),
+			_BlockPickHandler:        merge(parent._BlockPickHandler, own._BlockPickHandler),
+			_BlockPlaceHandler:       merge(parent._BlockPlaceHandler, own._BlockPlaceHandler),
+			_ChangeWorldHandler:      merge(parent._ChangeWorldHandler, own._ChangeWorldHandler),
+			_ChatHandler:             merge(parent._ChatHandler, own._ChatHandler),
+			_CommandExecutionHandler: merge(parent._CommandExecutionHandler, own._CommandExecutionHandler),
+			_DeathHandler:            merge(parent._DeathHandler, own._DeathHandler),
+			_DiagnosticsHandler:      merge(parent._DiagnosticsHandler, own._DiagnosticsHandler),
+			_ExperienceGainHandler:   merge(parent._ExperienceGainHandler, own._ExperienceGainHandler),
+			_FireExtinguishHandler:   merge(parent._FireExtinguishHandler, own._FireExtinguishHandler),
+			_FoodLossHandler:         merge(parent._FoodLossHandler, own._FoodLossHandler),
+			_HealHandler:             merge(parent._HealHandler, own._HealHandler),
+			_HeldSlotChangeHandler:   merge(parent._HeldSlotChangeHandler, own._HeldSlotChangeHandler),
+			_HurtHandler:             merge(parent._HurtHandler, own._HurtHandler),
+			_ItemConsumeHandler:      merge(parent._ItemConsumeHandler, own._ItemConsumeHandler),
+			_ItemDamageHandler:       merge(parent._ItemDamageHandler, own._ItemDamageHandler),
+			_ItemDropHandler:         merge(parent._ItemDropHandler, own._ItemDropHandler),
+			_ItemPickupHandler:       merge(parent._ItemPickupHandler, own._ItemPickupHandler),
+			_ItemReleaseHandler:      merge(parent._ItemReleaseHandler, own._ItemReleaseHandler),
+			_ItemUseHandler:          merge(parent._ItemUseHandler, own._ItemUseHandler),
+			_ItemUseOnBlockHandler:   merge(parent._ItemUseOnBlockHandler, own._ItemUseOnBlockHandler),
+			_ItemUseOnEntityHandler:  merge(parent._ItemUseOnEntityHandler, own._ItemUseOnEntityHandler),
+			_JumpHandler:             merge(parent._JumpHandler, own._JumpHandler),
+			_LecternPageTurnHandler:  merge(parent._LecternPageTurnHandler, own._LecternPageTurnHandler),
+			_MoveHandler:             merge(parent._MoveHandler, own._MoveHandler),
+			_PunchAirHandler:         merge(parent._PunchAirHandler, own._PunchAirHandler),
+			_QuitHandler:             merge(parent._QuitHandler, own._QuitHandler),
+			_RespawnHandler:          merge(parent._RespawnHandler, own._RespawnHandler),
+			_SignEditHandler:         merge(parent._SignEditHandler, own._SignEditHandler),
+			_SkinChangeHandler:       merge(parent._SkinChangeHandler, own._SkinChangeHandler),
+			_StartBreakHandler:       merge(parent._StartBreakHandler, own._StartBreakHandler),
+			_TeleportHandler:         merge(parent._TeleportHandler, own._TeleportHandler),
+			_ToggleSneakHandler:      merge(parent._ToggleSneakHandler, own._ToggleSneakHandler),
+			_ToggleSprintHandler:     merge(parent._ToggleSprintHandler, own._ToggleSprintHandler),
+			_TransferHandler:         merge(parent._TransferHandler, own._TransferHandler),
+		}
+	})
+}
+func (h *MultipleHandler) Register(hdr any, opts ...RegisterOption) func() {
+	r := newRegistration(opts)
+	var add, del []func(*multipleHandlerTable)
 	if hdr, ok := hdr.(MoveHandler); ok {
-		h._MoveHandler = append(h._MoveHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._MoveHandler = deleteVal(h._MoveHandler, hdr)
+		e := &entry[MoveHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._MoveHandler = insert(t._MoveHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._MoveHandler = deleteVal(t._MoveHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(JumpHandler); ok {
-		h._JumpHandler = append(h._JumpHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._JumpHandler = deleteVal(h._JumpHandler, hdr)
+		e := &entry[JumpHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._JumpHandler = insert(t._JumpHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._JumpHandler = deleteVal(t._JumpHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(TeleportHandler); ok {
-		h._TeleportHandler = append(h._TeleportHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._TeleportHandler = deleteVal(h._TeleportHandler, hdr)
+		e := &entry[TeleportHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._TeleportHandler = insert(t._TeleportHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._TeleportHandler = deleteVal(t._TeleportHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(ChangeWorldHandler); ok {
-		h._ChangeWorldHandler = append(h._ChangeWorldHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._ChangeWorldHandler = deleteVal(h._ChangeWorldHandler, hdr)
+		e := &entry[ChangeWorldHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._ChangeWorldHandler = insert(t._ChangeWorldHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._ChangeWorldHandler = deleteVal(t._ChangeWorldHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(ToggleSprintHandler); ok {
-		h._ToggleSprintHandler = append(h._ToggleSprintHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._ToggleSprintHandler = deleteVal(h._ToggleSprintHandler, hdr)
+		e := &entry[ToggleSprintHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._ToggleSprintHandler = insert(t._ToggleSprintHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._ToggleSprintHandler = deleteVal(t._ToggleSprintHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(ToggleSneakHandler); ok {
-		h._ToggleSneakHandler = append(h._ToggleSneakHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._ToggleSneakHandler = deleteVal(h._ToggleSneakHandler, hdr)
+		e := &entry[ToggleSneakHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._ToggleSneakHandler = insert(t._ToggleSneakHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._ToggleSneakHandler = deleteVal(t._ToggleSneakHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(ChatHandler); ok {
-		h._ChatHandler = append(h._ChatHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._ChatHandler = deleteVal(h._ChatHandler, hdr)
+		e := &entry[ChatHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._ChatHandler = insert(t._ChatHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._ChatHandler = deleteVal(t._ChatHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(FoodLossHandler); ok {
-		h._FoodLossHandler = append(h._FoodLossHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._FoodLossHandler = deleteVal(h._FoodLossHandler, hdr)
+		e := &entry[FoodLossHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._FoodLossHandler = insert(t._FoodLossHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._FoodLossHandler = deleteVal(t._FoodLossHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(HealHandler); ok {
-		h._HealHandler = append(h._HealHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._HealHandler = deleteVal(h._HealHandler, hdr)
+		e := &entry[HealHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._HealHandler = insert(t._HealHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._HealHandler = deleteVal(t._HealHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(HurtHandler); ok {
-		h._HurtHandler = append(h._HurtHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._HurtHandler = deleteVal(h._HurtHandler, hdr)
+		e := &entry[HurtHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._HurtHandler = insert(t._HurtHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._HurtHandler = deleteVal(t._HurtHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(DeathHandler); ok {
-		h._DeathHandler = append(h._DeathHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._DeathHandler = deleteVal(h._DeathHandler, hdr)
+		e := &entry[DeathHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._DeathHandler = insert(t._DeathHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._DeathHandler = deleteVal(t._DeathHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(RespawnHandler); ok {
-		h._RespawnHandler = append(h._RespawnHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._RespawnHandler = deleteVal(h._RespawnHandler, hdr)
+		e := &entry[RespawnHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._RespawnHandler = insert(t._RespawnHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._RespawnHandler = deleteVal(t._RespawnHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(SkinChangeHandler); ok {
-		h._SkinChangeHandler = append(h._SkinChangeHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._SkinChangeHandler = deleteVal(h._SkinChangeHandler, hdr)
+		e := &entry[SkinChangeHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._SkinChangeHandler = insert(t._SkinChangeHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._SkinChangeHandler = deleteVal(t._SkinChangeHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(FireExtinguishHandler); ok {
-		h._FireExtinguishHandler = append(h._FireExtinguishHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._FireExtinguishHandler = deleteVal(h._FireExtinguishHandler, hdr)
+		e := &entry[FireExtinguishHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._FireExtinguishHandler = insert(t._FireExtinguishHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._FireExtinguishHandler = deleteVal(t._FireExtinguishHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(StartBreakHandler); ok {
-		h._StartBreakHandler = append(h._StartBreakHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._StartBreakHandler = deleteVal(h._StartBreakHandler, hdr)
+		e := &entry[StartBreakHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._StartBreakHandler = insert(t._StartBreakHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._StartBreakHandler = deleteVal(t._StartBreakHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(BlockBreakHandler); ok {
-		h._BlockBreakHandler = append(h._BlockBreakHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._BlockBreakHandler = deleteVal(h._BlockBreakHandler, hdr)
+		e := &entry[BlockBreakHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._BlockBreakHandler = insert(t._BlockBreakHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._BlockBreakHandler = deleteVal(t._BlockBreakHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(BlockPlaceHandler); ok {
-		h._BlockPlaceHandler = append(h._BlockPlaceHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._BlockPlaceHandler = deleteVal(h._BlockPlaceHandler, hdr)
+		e := &entry[BlockPlaceHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._BlockPlaceHandler = insert(t._BlockPlaceHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._BlockPlaceHandler = deleteVal(t._BlockPlaceHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(BlockPickHandler); ok {
-		h._BlockPickHandler = append(h._BlockPickHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._BlockPickHandler = deleteVal(h._BlockPickHandler, hdr)
+		e := &entry[BlockPickHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._BlockPickHandler = insert(t._BlockPickHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._BlockPickHandler = deleteVal(t._BlockPickHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(ItemUseHandler); ok {
-		h._ItemUseHandler = append(h._ItemUseHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._ItemUseHandler = deleteVal(h._ItemUseHandler, hdr)
+		e := &entry[ItemUseHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._ItemUseHandler = insert(t._ItemUseHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._ItemUseHandler = deleteVal(t._ItemUseHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(ItemUseOnBlockHandler); ok {
-		h._ItemUseOnBlockHandler = append(h._ItemUseOnBlockHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._ItemUseOnBlockHandler = deleteVal(h._ItemUseOnBlockHandler, hdr)
+		e := &entry[ItemUseOnBlockHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._ItemUseOnBlockHandler = insert(t._ItemUseOnBlockHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._ItemUseOnBlockHandler = deleteVal(t._ItemUseOnBlockHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(ItemUseOnEntityHandler); ok {
-		h._ItemUseOnEntityHandler = append(h._ItemUseOnEntityHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._ItemUseOnEntityHandler = deleteVal(h._ItemUseOnEntityHandler, hdr)
+		e := &entry[ItemUseOnEntityHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._ItemUseOnEntityHandler = insert(t._ItemUseOnEntityHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._ItemUseOnEntityHandler = deleteVal(t._ItemUseOnEntityHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(ItemReleaseHandler); ok {
-		h._ItemReleaseHandler = append(h._ItemReleaseHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._ItemReleaseHandler = deleteVal(h._ItemReleaseHandler, hdr)
+		e := &entry[ItemReleaseHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._ItemReleaseHandler = insert(t._ItemReleaseHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._ItemReleaseHandler = deleteVal(t._ItemReleaseHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(ItemConsumeHandler); ok {
-		h._ItemConsumeHandler = append(h._ItemConsumeHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._ItemConsumeHandler = deleteVal(h._ItemConsumeHandler, hdr)
+		e := &entry[ItemConsumeHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._ItemConsumeHandler = insert(t._ItemConsumeHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._ItemConsumeHandler = deleteVal(t._ItemConsumeHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(AttackEntityHandler); ok {
-		h._AttackEntityHandler = append(h._AttackEntityHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._AttackEntityHandler = deleteVal(h._AttackEntityHandler, hdr)
+		e := &entry[AttackEntityHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._AttackEntityHandler = insert(t._AttackEntityHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._AttackEntityHandler = deleteVal(t._AttackEntityHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(ExperienceGainHandler); ok {
-		h._ExperienceGainHandler = append(h._ExperienceGainHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._ExperienceGainHandler = deleteVal(h._ExperienceGainHandler, hdr)
+		e := &entry[ExperienceGainHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._ExperienceGainHandler = insert(t._ExperienceGainHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._ExperienceGainHandler = deleteVal(t._ExperienceGainHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(PunchAirHandler); ok {
-		h._PunchAirHandler = append(h._PunchAirHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._PunchAirHandler = deleteVal(h._PunchAirHandler, hdr)
+		e := &entry[PunchAirHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._PunchAirHandler = insert(t._PunchAirHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._PunchAirHandler = deleteVal(t._PunchAirHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(SignEditHandler); ok {
-		h._SignEditHandler = append(h._SignEditHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._SignEditHandler = deleteVal(h._SignEditHandler, hdr)
+		e := &entry[SignEditHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._SignEditHandler = insert(t._SignEditHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._SignEditHandler = deleteVal(t._SignEditHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(LecternPageTurnHandler); ok {
-		h._LecternPageTurnHandler = append(h._LecternPageTurnHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._LecternPageTurnHandler = deleteVal(h._LecternPageTurnHandler, hdr)
+		e := &entry[LecternPageTurnHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._LecternPageTurnHandler = insert(t._LecternPageTurnHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._LecternPageTurnHandler = deleteVal(t._LecternPageTurnHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(ItemDamageHandler); ok {
-		h._ItemDamageHandler = append(h._ItemDamageHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._ItemDamageHandler = deleteVal(h._ItemDamageHandler, hdr)
+		e := &entry[ItemDamageHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._ItemDamageHandler = insert(t._ItemDamageHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._ItemDamageHandler = deleteVal(t._ItemDamageHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(ItemPickupHandler); ok {
-		h._ItemPickupHandler = append(h._ItemPickupHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._ItemPickupHandler = deleteVal(h._ItemPickupHandler, hdr)
+		e := &entry[ItemPickupHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._ItemPickupHandler = insert(t._ItemPickupHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._ItemPickupHandler = deleteVal(t._ItemPickupHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(HeldSlotChangeHandler); ok {
-		h._HeldSlotChangeHandler = append(h._HeldSlotChangeHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._HeldSlotChangeHandler = deleteVal(h._HeldSlotChangeHandler, hdr)
+		e := &entry[HeldSlotChangeHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._HeldSlotChangeHandler = insert(t._HeldSlotChangeHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._HeldSlotChangeHandler = deleteVal(t._HeldSlotChangeHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(ItemDropHandler); ok {
-		h._ItemDropHandler = append(h._ItemDropHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._ItemDropHandler = deleteVal(h._ItemDropHandler, hdr)
+		e := &entry[ItemDropHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._ItemDropHandler = insert(t._ItemDropHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._ItemDropHandler = deleteVal(t._ItemDropHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(TransferHandler); ok {
-		h._TransferHandler = append(h._TransferHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._TransferHandler = deleteVal(h._TransferHandler, hdr)
+		e := &entry[TransferHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._TransferHandler = insert(t._TransferHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._TransferHandler = deleteVal(t._TransferHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(CommandExecutionHandler); ok {
-		h._CommandExecutionHandler = append(h._CommandExecutionHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._CommandExecutionHandler = deleteVal(h._CommandExecutionHandler, hdr)
+		e := &entry[CommandExecutionHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._CommandExecutionHandler = insert(t._CommandExecutionHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._CommandExecutionHandler = deleteVal(t._CommandExecutionHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(QuitHandler); ok {
-		h._QuitHandler = append(h._QuitHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._QuitHandler = deleteVal(h._QuitHandler, hdr)
+		e := &entry[QuitHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._QuitHandler = insert(t._QuitHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._QuitHandler = deleteVal(t._QuitHandler, e)
 		})
 	}
 	if hdr, ok := hdr.(DiagnosticsHandler); ok {
-		h._DiagnosticsHandler = append(h._DiagnosticsHandler, hdr)
-		reg = true
-		funcs = append(funcs, func() {
-			h._DiagnosticsHandler = deleteVal(h._DiagnosticsHandler, hdr)
+		e := &entry[DiagnosticsHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleHandlerTable) {
+			t._DiagnosticsHandler = insert(t._DiagnosticsHandler, e)
+		})
+		del = append(del, func(t *multipleHandlerTable) {
+			t._DiagnosticsHandler = deleteVal(t._DiagnosticsHandler, e)
 		})
 	}
-	if !reg {
+	if len(add) == 0 {
 		panic("not a valid handler")
 	}
+	h.update(add...)
 	return func() {
-		for _, f := range funcs {
-			f()
-		}
+		h.update(del...)
 	}
 }
 func (h *MultipleHandler) Clear() {
-	h._MoveHandler = nil
-	h._JumpHandler = nil
-	h._TeleportHandler = nil
-	h._ChangeWorldHandler = nil
-	h._ToggleSprintHandler = nil
-	h._ToggleSneakHandler = nil
-	h._ChatHandler = nil
-	h._FoodLossHandler = nil
-	h._HealHandler = nil
-	h._HurtHandler = nil
-	h._DeathHandler = nil
-	h._RespawnHandler = nil
-	h._SkinChangeHandler = nil
-	h._FireExtinguishHandler = nil
-	h._StartBreakHandler = nil
-	h._BlockBreakHandler = nil
-	h._BlockPlaceHandler = nil
-	h._BlockPickHandler = nil
-	h._ItemUseHandler = nil
-	h._ItemUseOnBlockHandler = nil
-	h._ItemUseOnEntityHandler = nil
-	h._ItemReleaseHandler = nil
-	h._ItemConsumeHandler = nil
-	h._AttackEntityHandler = nil
-	h._ExperienceGainHandler = nil
-	h._PunchAirHandler = nil
-	h._SignEditHandler = nil
-	h._LecternPageTurnHandler = nil
-	h._ItemDamageHandler = nil
-	h._ItemPickupHandler = nil
-	h._HeldSlotChangeHandler = nil
-	h._ItemDropHandler = nil
-	h._TransferHandler = nil
-	h._CommandExecutionHandler = nil
-	h._QuitHandler = nil
-	h._DiagnosticsHandler = nil
+	h.clear()
 }
@@ -0,0 +1,313 @@
+// Code generated by tools/gen_handlers.go from github.com/df-mc/dragonfly@v0.10.1. DO NOT EDIT.
+
+package mhandler
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+type WorldLiquidFlowHandler interface {
+	// HandleLiquidFlow handles the flowing of a liquid from one block position
+	// from into another block position into. The liquid that will replace the
+	// block is also passed. This replaced block might also be a Liquid. The
+	// Liquid's depth and falling state can be checked to see if the resulting
+	// liquid is a new source block (in the case of water).
+	HandleLiquidFlow(ctx *event.Context[*world.Tx], from, into cube.Pos, liquid world.Liquid, replaced world.Block)
+}
+type WorldLiquidDecayHandler interface {
+	// HandleLiquidDecay handles the decaying of a Liquid block at a position.
+	// Liquid decaying happens when there is no Liquid that can serve as the
+	// source block neighbouring it. The state of the Liquid before and after
+	// the decaying is passed. The Liquid after is nil if the liquid is
+	// completely removed as a result of the decay.
+	HandleLiquidDecay(ctx *event.Context[*world.Tx], pos cube.Pos, before, after world.Liquid)
+}
+type WorldLiquidHardenHandler interface {
+	// HandleLiquidHarden handles the hardening of a liquid at hardenedPos. The
+	// liquid that was hardened, liquidHardened, and the liquid that caused it
+	// to harden, otherLiquid, are passed. The block created as a result is also
+	// passed.
+	HandleLiquidHarden(ctx *event.Context[*world.Tx], hardenedPos cube.Pos, liquidHardened, otherLiquid, newBlock world.Block)
+}
+type WorldSoundHandler interface {
+	// HandleSound handles a Sound being played in the World at a specific
+	// position. ctx.Cancel() may be called to stop the Sound from playing to
+	// viewers of the position.
+	HandleSound(ctx *event.Context[*world.Tx], s world.Sound, pos mgl64.Vec3)
+}
+type WorldFireSpreadHandler interface {
+	// HandleFireSpread handles when a fire block spreads from one block to
+	// another block. When this event handler gets called, both the position of
+	// the original fire will be passed, and the position where it will spread
+	// to after the event. The age of the fire may also be altered by changing
+	// the underlying value of the newFireAge pointer, which decides how long
+	// the fire will stay before burning out.
+	HandleFireSpread(ctx *event.Context[*world.Tx], from, to cube.Pos)
+}
+type WorldBlockBurnHandler interface {
+	// HandleBlockBurn handles a block at a cube.Pos being burnt by fire. This
+	// event may be called for blocks such as wood, that can be broken by fire.
+	// HandleBlockBurn is often succeeded by HandleFireSpread, when fire spreads
+	// to the position of the original block and the Context is not cancelled in
+	// HandleBlockBurn.
+	HandleBlockBurn(ctx *event.Context[*world.Tx], pos cube.Pos)
+}
+type WorldCropTrampleHandler interface {
+	// HandleCropTrample handles an Entity trampling a crop.
+	HandleCropTrample(ctx *event.Context[*world.Tx], pos cube.Pos)
+}
+type WorldLeavesDecayHandler interface {
+	// HandleLeavesDecay handles the decaying of a Leaves block at a position.
+	// Leaves decaying happens when there is no wood block neighbouring it.
+	// ctx.Cancel() may be called to prevent leaves from decaying.
+	HandleLeavesDecay(ctx *event.Context[*world.Tx], pos cube.Pos)
+}
+type WorldEntitySpawnHandler interface {
+	// HandleEntitySpawn handles an Entity being spawned into a World through a
+	// call to Tx.AddEntity.
+	HandleEntitySpawn(tx *world.Tx, e world.Entity)
+}
+type WorldEntityDespawnHandler interface {
+	// HandleEntityDespawn handles an Entity being despawned from a World
+	// through a call to Tx.RemoveEntity.
+	HandleEntityDespawn(tx *world.Tx, e world.Entity)
+}
+type WorldCloseHandler interface {
+	// HandleClose handles the World being closed. HandleClose may be used as a
+	// moment to finish code running on other goroutines that operates on the
+	// World specifically. HandleClose is called directly before the World stops
+	// ticking and before any chunks are saved to disk.
+	HandleClose(tx *world.Tx)
+}
+
+func (h *MultipleWorldHandler) HandleLiquidFlow(ctx *event.Context[*world.Tx], from, into cube.Pos, liquid world.Liquid, replaced world.Block) {
+	for _, ent := range h.merged()._WorldLiquidFlowHandler {
+		if ent.skip(ctx) {
+			continue
+		}
+		ent.hdr.HandleLiquidFlow(ctx, from, into, liquid, replaced)
+	}
+}
+func (h *MultipleWorldHandler) HandleLiquidDecay(ctx *event.Context[*world.Tx], pos cube.Pos, before, after world.Liquid) {
+	for _, ent := range h.merged()._WorldLiquidDecayHandler {
+		if ent.skip(ctx) {
+			continue
+		}
+		ent.hdr.HandleLiquidDecay(ctx, pos, before, after)
+	}
+}
+func (h *MultipleWorldHandler) HandleLiquidHarden(ctx *event.Context[*world.Tx], hardenedPos cube.Pos, liquidHardened, otherLiquid, newBlock world.Block) {
+	for _, ent := range h.merged()._WorldLiquidHardenHandler {
+		if ent.skip(ctx) {
+			continue
+		}
+		ent.hdr.HandleLiquidHarden(ctx, hardenedPos, liquidHardened, otherLiquid, newBlock)
+	}
+}
+func (h *MultipleWorldHandler) HandleSound(ctx *event.Context[*world.Tx], s world.Sound, pos mgl64.Vec3) {
+	for _, ent := range h.merged()._WorldSoundHandler {
+		if ent.skip(ctx) {
+			continue
+		}
+		ent.hdr.HandleSound(ctx, s, pos)
+	}
+}
+func (h *MultipleWorldHandler) HandleFireSpread(ctx *event.Context[*world.Tx], from, to cube.Pos) {
+	for _, ent := range h.merged()._WorldFireSpreadHandler {
+		if ent.skip(ctx) {
+			continue
+		}
+		ent.hdr.HandleFireSpread(ctx, from, to)
+	}
+}
+func (h *MultipleWorldHandler) HandleBlockBurn(ctx *event.Context[*world.Tx], pos cube.Pos) {
+	for _, ent := range h.merged()._WorldBlockBurnHandler {
+		if ent.skip(ctx) {
+			continue
+		}
+		ent.hdr.HandleBlockBurn(ctx, pos)
+	}
+}
+func (h *MultipleWorldHandler) HandleCropTrample(ctx *event.Context[*world.Tx], pos cube.Pos) {
+	for _, ent := range h.merged()._WorldCropTrampleHandler {
+		if ent.skip(ctx) {
+			continue
+		}
+		ent.hdr.HandleCropTrample(ctx, pos)
+	}
+}
+func (h *MultipleWorldHandler) HandleLeavesDecay(ctx *event.Context[*world.Tx], pos cube.Pos) {
+	for _, ent := range h.merged()._WorldLeavesDecayHandler {
+		if ent.skip(ctx) {
+			continue
+		}
+		ent.hdr.HandleLeavesDecay(ctx, pos)
+	}
+}
+func (h *MultipleWorldHandler) HandleEntitySpawn(tx *world.Tx, e world.Entity) {
+	for _, ent := range h.merged()._WorldEntitySpawnHandler {
+		ent.hdr.HandleEntitySpawn(tx, e)
+	}
+}
+func (h *MultipleWorldHandler) HandleEntityDespawn(tx *world.Tx, e world.Entity) {
+	for _, ent := range h.merged()._WorldEntityDespawnHandler {
+		ent.hdr.HandleEntityDespawn(tx, e)
+	}
+}
+func (h *MultipleWorldHandler) HandleClose(tx *world.Tx) {
+	for _, ent := range h.merged()._WorldCloseHandler {
+		ent.hdr.HandleClose(tx)
+	}
+}
+
+type multipleWorldHandlerTable struct {
+	_WorldLiquidFlowHandler    []*entry[WorldLiquidFlowHandler]
+	_WorldLiquidDecayHandler   []*entry[WorldLiquidDecayHandler]
+	_WorldLiquidHardenHandler  []*entry[WorldLiquidHardenHandler]
+	_WorldSoundHandler         []*entry[WorldSoundHandler]
+	_WorldFireSpreadHandler    []*entry[WorldFireSpreadHandler]
+	_WorldBlockBurnHandler     []*entry[WorldBlockBurnHandler]
+	_WorldCropTrampleHandler   []*entry[WorldCropTrampleHandler]
+	_WorldLeavesDecayHandler   []*entry[WorldLeavesDecayHandler]
+	_WorldEntitySpawnHandler   []*entry[WorldEntitySpawnHandler]
+	_WorldEntityDespawnHandler []*entry[WorldEntityDespawnHandler]
+	_WorldCloseHandler         []*entry[WorldCloseHandler]
+}
+type MultipleWorldHandler struct {
+	handlers[multipleWorldHandlerTable]
+}
+
+// merged returns the handlers registered to h merged with those of its parent, sorted by priority.
+func (h *MultipleWorldHandler) merged() *multipleWorldHandlerTable {
+	return h.mergedWith(func(parent, own *multipleWorldHandlerTable) *multipleWorldHandlerTable {
+		return &multipleWorldHandlerTable{
+			_WorldBlockBurnHandler:     merge(parent._WorldBlockBurnHandler, own._WorldBlockBurnHandler),
+			_WorldCloseHandler:         merge(parent._WorldCloseHandler, own._WorldCloseHandler),
+			_WorldCropTrampleHandler:   merge(parent._WorldCropTrampleHandler, own._WorldCropTrampleHandler),
+			_WorldEntityDespawnHandler: merge(parent._WorldEntityDespawnHandler, own._WorldEntityDespawnHandler),
+			_WorldEntitySpawnHandler:   merge(parent._WorldEntitySpawnHandler, own._WorldEntitySpawnHandler),
+			_WorldFireSpreadHandler:    merge(parent._WorldFireSpreadHandler, own._WorldFireSpreadHandler),
+			_WorldLeavesDecayHandler:   merge(parent._WorldLeavesDecayHandler, own._WorldLeavesDecayHandler),
+			_WorldLiquidDecayHandler:   merge(parent._WorldLiquidDecayHandler, own._WorldLiquidDecayHandler),
+			_WorldLiquidFlowHandler:    merge(parent._WorldLiquidFlowHandler, own._WorldLiquidFlowHandler),
+			_WorldLiquidHardenHandler:  merge(parent._WorldLiquidHardenHandler, own._WorldLiquidHardenHandler),
+			_WorldSoundHandler:         merge(parent._WorldSoundHandler, own._WorldSoundHandler),
+		}
+	})
+}
+func (h *MultipleWorldHandler) Register(hdr any, opts ...RegisterOption) func() {
+	r := newRegistration(opts)
+	var add, del []func(*multipleWorldHandlerTable)
+	if hdr, ok := hdr.(WorldLiquidFlowHandler); ok {
+		e := &entry[WorldLiquidFlowHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleWorldHandlerTable) {
+			t._WorldLiquidFlowHandler = insert(t._WorldLiquidFlowHandler, e)
+		})
+		del = append(del, func(t *multipleWorldHandlerTable) {
+			t._WorldLiquidFlowHandler = deleteVal(t._WorldLiquidFlowHandler, e)
+		})
+	}
+	if hdr, ok := hdr.(WorldLiquidDecayHandler); ok {
+		e := &entry[WorldLiquidDecayHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleWorldHandlerTable) {
+			t._WorldLiquidDecayHandler = insert(t._WorldLiquidDecayHandler, e)
+		})
+		del = append(del, func(t *multipleWorldHandlerTable) {
+			t._WorldLiquidDecayHandler = deleteVal(t._WorldLiquidDecayHandler, e)
+		})
+	}
+	if hdr, ok := hdr.(WorldLiquidHardenHandler); ok {
+		e := &entry[WorldLiquidHardenHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleWorldHandlerTable) {
+			t._WorldLiquidHardenHandler = insert(t._WorldLiquidHardenHandler, e)
+		})
+		del = append(del, func(t *multipleWorldHandlerTable) {
+			t._WorldLiquidHardenHandler = deleteVal(t._WorldLiquidHardenHandler, e)
+		})
+	}
+	if hdr, ok := hdr.(WorldSoundHandler); ok {
+		e := &entry[WorldSoundHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleWorldHandlerTable) {
+			t._WorldSoundHandler = insert(t._WorldSoundHandler, e)
+		})
+		del = append(del, func(t *multipleWorldHandlerTable) {
+			t._WorldSoundHandler = deleteVal(t._WorldSoundHandler, e)
+		})
+	}
+	if hdr, ok := hdr.(WorldFireSpreadHandler); ok {
+		e := &entry[WorldFireSpreadHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleWorldHandlerTable) {
+			t._WorldFireSpreadHandler = insert(t._WorldFireSpreadHandler, e)
+		})
+		del = append(del, func(t *multipleWorldHandlerTable) {
+			t._WorldFireSpreadHandler = deleteVal(t._WorldFireSpreadHandler, e)
+		})
+	}
+	if hdr, ok := hdr.(WorldBlockBurnHandler); ok {
+		e := &entry[WorldBlockBurnHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleWorldHandlerTable) {
+			t._WorldBlockBurnHandler = insert(t._WorldBlockBurnHandler, e)
+		})
+		del = append(del, func(t *multipleWorldHandlerTable) {
+			t._WorldBlockBurnHandler = deleteVal(t._WorldBlockBurnHandler, e)
+		})
+	}
+	if hdr, ok := hdr.(WorldCropTrampleHandler); ok {
+		e := &entry[WorldCropTrampleHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleWorldHandlerTable) {
+			t._WorldCropTrampleHandler = insert(t._WorldCropTrampleHandler, e)
+		})
+		del = append(del, func(t *multipleWorldHandlerTable) {
+			t._WorldCropTrampleHandler = deleteVal(t._WorldCropTrampleHandler, e)
+		})
+	}
+	if hdr, ok := hdr.(WorldLeavesDecayHandler); ok {
+		e := &entry[WorldLeavesDecayHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleWorldHandlerTable) {
+			t._WorldLeavesDecayHandler = insert(t._WorldLeavesDecayHandler, e)
+		})
+		del = append(del, func(t *multipleWorldHandlerTable) {
+			t._WorldLeavesDecayHandler = deleteVal(t._WorldLeavesDecayHandler, e)
+		})
+	}
+	if hdr, ok := hdr.(WorldEntitySpawnHandler); ok {
+		e := &entry[WorldEntitySpawnHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleWorldHandlerTable) {
+			t._WorldEntitySpawnHandler = insert(t._WorldEntitySpawnHandler, e)
+		})
+		del = append(del, func(t *multipleWorldHandlerTable) {
+			t._WorldEntitySpawnHandler = deleteVal(t._WorldEntitySpawnHandler, e)
+		})
+	}
+	if hdr, ok := hdr.(WorldEntityDespawnHandler); ok {
+		e := &entry[WorldEntityDespawnHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleWorldHandlerTable) {
+			t._WorldEntityDespawnHandler = insert(t._WorldEntityDespawnHandler, e)
+		})
+		del = append(del, func(t *multipleWorldHandlerTable) {
+			t._WorldEntityDespawnHandler = deleteVal(t._WorldEntityDespawnHandler, e)
+		})
+	}
+	if hdr, ok := hdr.(WorldCloseHandler); ok {
+		e := &entry[WorldCloseHandler]{registration: r, hdr: hdr}
+		add = append(add, func(t *multipleWorldHandlerTable) {
+			t._WorldCloseHandler = insert(t._WorldCloseHandler, e)
+		})
+		del = append(del, func(t *multipleWorldHandlerTable) {
+			t._WorldCloseHandler = deleteVal(t._WorldCloseHandler, e)
+		})
+	}
+	if len(add) == 0 {
+		panic("not a valid handler")
+	}
+	h.update(add...)
+	return func() {
+		h.update(del...)
+	}
+}
+func (h *MultipleWorldHandler) Clear() {
+	h.clear()
+}
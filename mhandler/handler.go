@@ -1,6 +1,16 @@
 package mhandler
 
-import "golang.org/x/exp/slices"
+//go:generate go run ../tools/gen_handlers.go -pkg github.com/df-mc/dragonfly/server/player -name MultipleHandler -out generated.go
+//go:generate go run ../tools/gen_handlers.go -pkg github.com/df-mc/dragonfly/server/world -name MultipleWorldHandler -prefix World -out generated_world.go
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"golang.org/x/exp/slices"
+)
 
 // Index returns the index of the first occurrence of v in s, or -1 if not
 // present. Index accepts any type, as opposed to slices.Index, but might panic
@@ -15,14 +25,212 @@ func index[E any](s []E, v E) int {
 }
 
 // deleteVal deletes the first occurrence of a value in a slice of the type E
-// and returns a new slice without the value.
+// and returns a new slice without the value. s itself is never modified.
 func deleteVal[E any](s []E, v E) []E {
 	if i := index(s, v); i != -1 {
-		return slices.Clone(slices.Delete(s, i, i+1))
+		return slices.Delete(slices.Clone(s), i, i+1)
 	}
 	return s
 }
 
+// Priority is the priority of a handler registered to a MultipleHandler or MultipleWorldHandler. Handlers with a lower priority are
+// called first, so that handlers with a higher priority have the final say over the outcome of an event.
+type Priority int
+
+const (
+	PriorityLowest Priority = iota
+	PriorityLow
+	PriorityNormal
+	PriorityHigh
+	PriorityHighest
+	// PriorityMonitor handlers are called last and should only observe the outcome of an event. They are
+	// always called, even if the event was cancelled and the handler was registered with IgnoreCancelled.
+	PriorityMonitor
+)
+
+// registration holds the options a handler was registered with.
+type registration struct {
+	priority        Priority
+	ignoreCancelled bool
+}
+
+// RegisterOption is an option that may be passed to MultipleHandler.Register and MultipleWorldHandler.Register.
+type RegisterOption func(*registration)
+
+// WithPriority sets the priority of the handler registered. Handlers are registered with PriorityNormal by
+// default.
+func WithPriority(p Priority) RegisterOption {
+	return func(r *registration) {
+		r.priority = p
+	}
+}
+
+// IgnoreCancelled makes the handler registered skip events that were already cancelled by a handler called
+// before it. It has no effect on handlers with PriorityMonitor.
+func IgnoreCancelled() RegisterOption {
+	return func(r *registration) {
+		r.ignoreCancelled = true
+	}
+}
+
+func newRegistration(opts []RegisterOption) registration {
+	r := registration{priority: PriorityNormal}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return r
+}
+
+// cancellable is implemented by the event.Context passed to cancellable events.
+type cancellable interface {
+	Cancelled() bool
+}
+
+// entry is a handler registered to a MultipleHandler together with its registration options.
+type entry[H any] struct {
+	registration
+	hdr H
+}
+
+// skip reports if the handler should not be called for the event with the context passed.
+func (e *entry[H]) skip(ctx cancellable) bool {
+	return e.ignoreCancelled && e.priority != PriorityMonitor && ctx.Cancelled()
+}
+
+// insert inserts e into s after all entries with a priority lower than or equal to that of e, keeping
+// handlers with the same priority in registration order.
+func insert[H any](s []*entry[H], e *entry[H]) []*entry[H] {
+	i := len(s)
+	for i > 0 && s[i-1].priority > e.priority {
+		i--
+	}
+	return slices.Insert(slices.Clone(s), i, e)
+}
+
+// merge merges the entries of a parent handler and its child, both sorted by priority, into one slice sorted
+// by priority. Entries of the parent are called before those of the child with the same priority.
+func merge[H any](parent, child []*entry[H]) []*entry[H] {
+	if len(parent) == 0 {
+		return child
+	}
+	if len(child) == 0 {
+		return parent
+	}
+	s := make([]*entry[H], 0, len(parent)+len(child))
+	for len(parent) > 0 && len(child) > 0 {
+		if child[0].priority < parent[0].priority {
+			s, child = append(s, child[0]), child[1:]
+		} else {
+			s, parent = append(s, parent[0]), parent[1:]
+		}
+	}
+	return append(append(s, parent...), child...)
+}
+
+// handlers holds a copy-on-write table of registered handlers of the type T. Events are dispatched to an
+// immutable snapshot of the table, so that handlers may be registered and unregistered from any goroutine,
+// including from within their own callbacks, without affecting an event that is currently being handled.
+type handlers[T any] struct {
+	mu     sync.Mutex
+	table  atomic.Pointer[T]
+	parent *handlers[T]
+	// cache holds the table merged with that of the parent, if there is a parent.
+	cache atomic.Pointer[mergedTable[T]]
+}
+
+// mergedTable is the table of a child merged with that of its parent, which remains valid for as long as
+// neither of the snapshots it was merged from is replaced.
+type mergedTable[T any] struct {
+	parent, own *T
+	table       *T
+}
+
+// load returns the current snapshot of the table. The table returned must not be modified.
+func (h *handlers[T]) load() *T {
+	if t := h.table.Load(); t != nil {
+		return t
+	}
+	h.table.CompareAndSwap(nil, new(T))
+	return h.table.Load()
+}
+
+// mergedWith returns the current snapshot of the table merged with that of the parent using the function
+// passed, or the snapshot of the table itself if there is no parent. The merged table is cached until either
+// snapshot is replaced, so that dispatching an event does not allocate. The table returned must not be
+// modified.
+func (h *handlers[T]) mergedWith(merge func(parent, own *T) *T) *T {
+	if h.parent == nil {
+		return h.load()
+	}
+	parent, own := h.parent.load(), h.load()
+	if m := h.cache.Load(); m != nil && m.parent == parent && m.own == own {
+		return m.table
+	}
+	t := merge(parent, own)
+	h.cache.Store(&mergedTable[T]{parent: parent, own: own, table: t})
+	return t
+}
+
+// update applies all functions passed to a copy of the current table and atomically replaces the table
+// with it. The functions must not modify the slices held by the table in place.
+func (h *handlers[T]) update(fs ...func(*T)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	t := *h.load()
+	for _, f := range fs {
+		f(&t)
+	}
+	h.table.Store(&t)
+}
+
+// clear removes all handlers from the table.
+func (h *handlers[T]) clear() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.table.Store(new(T))
+}
+
+// Compile time checks to make sure the generated handlers implement the handler interfaces of dragonfly.
+var (
+	_ player.Handler = (*MultipleHandler)(nil)
+	_ world.Handler  = (*MultipleWorldHandler)(nil)
+)
+
 func New() *MultipleHandler {
 	return &MultipleHandler{}
 }
+
+// NewWorld returns a new MultipleWorldHandler, which may be set as the handler of a world using
+// world.World.Handle to call any number of world handlers registered to it.
+func NewWorld() *MultipleWorldHandler {
+	return &MultipleWorldHandler{}
+}
+
+// Inherit returns a new MultipleHandler that calls the handlers registered to parent in addition to its own.
+// Handlers of both are called in the order of their priorities, with those of parent being called first if
+// the priorities are equal.
+func Inherit(parent *MultipleHandler) *MultipleHandler {
+	h := New()
+	h.parent = &parent.handlers
+	return h
+}
+
+var global = New()
+
+// Global returns the server-wide MultipleHandler. Handlers registered to it are called for every player that
+// has a handler attached using Attach.
+func Global() *MultipleHandler {
+	return global
+}
+
+// Attach returns the MultipleHandler of the player passed. If the player does not yet have one, a new
+// MultipleHandler inheriting the Global handlers is created and set as the handler of the player.
+// Attach must be called within the transaction of the player.
+func Attach(p *player.Player) *MultipleHandler {
+	if h, ok := p.Handler().(*MultipleHandler); ok {
+		return h
+	}
+	h := Inherit(Global())
+	p.Handle(h)
+	return h
+}
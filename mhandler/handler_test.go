@@ -0,0 +1,146 @@
+package mhandler
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/player"
+)
+
+// chatFunc is a ChatHandler calling a function.
+type chatFunc func(ctx *event.Context[*player.Player], message *string)
+
+func (f chatFunc) HandleChat(ctx *event.Context[*player.Player], message *string) {
+	f(ctx, message)
+}
+
+func TestDispatchDoesNotAllocate(t *testing.T) {
+	parent := New()
+	h := Inherit(parent)
+	parent.Register(chatFunc(func(*event.Context[*player.Player], *string) {}))
+	h.Register(chatFunc(func(*event.Context[*player.Player], *string) {}))
+
+	ctx := event.C[*player.Player](nil)
+	message := "hello"
+	h.HandleChat(ctx, &message)
+	if allocs := testing.AllocsPerRun(100, func() {
+		h.HandleChat(ctx, &message)
+	}); allocs != 0 {
+		t.Fatalf("dispatching allocated %v times, expected 0", allocs)
+	}
+}
+
+// chat dispatches a chat event with the message passed to the MultipleHandler and returns its context.
+func chat(h *MultipleHandler, message string) *event.Context[*player.Player] {
+	ctx := event.C[*player.Player](nil)
+	h.HandleChat(ctx, &message)
+	return ctx
+}
+
+// recorder returns a ChatHandler appending the name passed to calls when it is called.
+func recorder(calls *[]string, name string) chatFunc {
+	return func(*event.Context[*player.Player], *string) {
+		*calls = append(*calls, name)
+	}
+}
+
+func TestConcurrentRegister(t *testing.T) {
+	h := Inherit(New())
+	var calls atomic.Int64
+	counter := chatFunc(func(*event.Context[*player.Player], *string) {
+		calls.Add(1)
+	})
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					chat(h, "hello")
+				}
+			}
+		}()
+	}
+	for i := 0; i < 1000; i++ {
+		unregister := h.Register(counter, WithPriority(Priority(i%int(PriorityMonitor+1))))
+		if i%2 == 0 {
+			unregister()
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	calls.Store(0)
+	chat(h, "hello")
+	if n := calls.Load(); n != 500 {
+		t.Fatalf("expected 500 handlers to be called, got %v", n)
+	}
+}
+
+func TestUnregisterInCallback(t *testing.T) {
+	h := New()
+	var calls []string
+	var unregister func()
+	unregister = h.Register(chatFunc(func(*event.Context[*player.Player], *string) {
+		calls = append(calls, "once")
+		unregister()
+	}))
+	h.Register(recorder(&calls, "always"))
+
+	chat(h, "hello")
+	chat(h, "hello")
+	if want := []string{"once", "always", "always"}; !slices.Equal(calls, want) {
+		t.Fatalf("expected calls %v, got %v", want, calls)
+	}
+}
+
+func TestInheritOrder(t *testing.T) {
+	var calls []string
+	unregister := Global().Register(recorder(&calls, "global"))
+	defer unregister()
+
+	parent := Inherit(Global())
+	parent.Register(recorder(&calls, "parent high"), WithPriority(PriorityHigh))
+	parent.Register(recorder(&calls, "parent"))
+	child := Inherit(Global())
+	child.Register(recorder(&calls, "child"))
+	child.Register(recorder(&calls, "child lowest"), WithPriority(PriorityLowest))
+
+	chat(parent, "hello")
+	if want := []string{"global", "parent", "parent high"}; !slices.Equal(calls, want) {
+		t.Fatalf("expected calls %v, got %v", want, calls)
+	}
+	calls = nil
+	chat(child, "hello")
+	if want := []string{"child lowest", "global", "child"}; !slices.Equal(calls, want) {
+		t.Fatalf("expected calls %v, got %v", want, calls)
+	}
+}
+
+func TestCancellation(t *testing.T) {
+	h := New()
+	var calls []string
+	h.Register(recorder(&calls, "monitor"), WithPriority(PriorityMonitor), IgnoreCancelled())
+	h.Register(recorder(&calls, "ignore cancelled"), WithPriority(PriorityHigh), IgnoreCancelled())
+	h.Register(recorder(&calls, "high"), WithPriority(PriorityHigh))
+	h.Register(chatFunc(func(ctx *event.Context[*player.Player], _ *string) {
+		calls = append(calls, "cancel")
+		ctx.Cancel()
+	}))
+
+	if ctx := chat(h, "hello"); !ctx.Cancelled() {
+		t.Fatal("expected the event to be cancelled")
+	}
+	if want := []string{"cancel", "high", "monitor"}; !slices.Equal(calls, want) {
+		t.Fatalf("expected calls %v, got %v", want, calls)
+	}
+}
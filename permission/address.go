@@ -0,0 +1,111 @@
+package permission
+
+import (
+	"container/list"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+)
+
+// ParseAddress parses an IP address or a CIDR range, such as 192.168.0.1 or 192.168.0.0/24, and returns it in
+// its canonical form.
+func ParseAddress(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "/") {
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid CIDR range %q", s)
+		}
+		return n.String(), nil
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return "", fmt.Errorf("invalid IP address %q", s)
+	}
+	return ip.String(), nil
+}
+
+// matchAddress checks if the IP passed is the IP address or within the CIDR range passed.
+func matchAddress(address string, ip net.IP) bool {
+	if ip == nil || address == "" {
+		return false
+	}
+	if strings.Contains(address, "/") {
+		_, n, err := net.ParseCIDR(address)
+		return err == nil && n.Contains(ip)
+	}
+	a := net.ParseIP(address)
+	return a != nil && a.Equal(ip)
+}
+
+// IPOf returns the IP of the net.Addr passed, or nil if it has none.
+func IPOf(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case nil:
+		return nil
+	case *net.UDPAddr:
+		return a.IP
+	case *net.TCPAddr:
+		return a.IP
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		host = addr.String()
+	}
+	return net.ParseIP(host)
+}
+
+// Identity is the identity a player last tried to join the server with.
+type Identity struct {
+	Name string
+	XUID string
+	IP   net.IP
+}
+
+// MaxIdentities is the number of identities of players that tried to join the server remembered for
+// LastIdentity. When more players try to join, the identity of the player that tried to join the longest ago is
+// forgotten.
+var MaxIdentities = 1024
+
+var identities = struct {
+	mu    sync.Mutex
+	order *list.List
+	names map[string]*list.Element
+}{order: list.New(), names: map[string]*list.Element{}}
+
+// remember stores the identity of a player trying to join the server, so that it may be looked up using
+// LastIdentity, for example to ban the IP of a player. At most MaxIdentities identities are kept.
+func remember(addr net.Addr, d login.IdentityData) {
+	name := strings.ToLower(d.DisplayName)
+	id := Identity{Name: d.DisplayName, XUID: d.XUID, IP: IPOf(addr)}
+
+	identities.mu.Lock()
+	defer identities.mu.Unlock()
+	if e, ok := identities.names[name]; ok {
+		e.Value = id
+		identities.order.MoveToFront(e)
+		return
+	}
+	identities.names[name] = identities.order.PushFront(id)
+	for identities.order.Len() > max(MaxIdentities, 1) {
+		e := identities.order.Back()
+		identities.order.Remove(e)
+		delete(identities.names, strings.ToLower(e.Value.(Identity).Name))
+	}
+}
+
+// LastIdentity returns the identity the player with the name passed last tried to join the server with since
+// it was started, if it is one of the last MaxIdentities players that tried to join. Names are compared
+// case-insensitively.
+func LastIdentity(name string) (Identity, bool) {
+	identities.mu.Lock()
+	defer identities.mu.Unlock()
+	e, ok := identities.names[strings.ToLower(name)]
+	if !ok {
+		return Identity{}, false
+	}
+	return e.Value.(Identity), true
+}
@@ -21,9 +21,10 @@ type entryServerAllower struct {
 	msg       string
 }
 
-func (e entryServerAllower) Allow(_ net.Addr, d login.IdentityData, _ login.ClientData) (string, bool) {
+func (e entryServerAllower) Allow(addr net.Addr, d login.IdentityData, _ login.ClientData) (string, bool) {
+	has := e.e.Match(d.DisplayName, d.XUID, IPOf(addr))
 	if e.detectHas {
-		return e.msg, e.e.Has(d.DisplayName)
+		return e.msg, has
 	}
-	return e.msg, !e.e.Has(d.DisplayName)
+	return e.msg, !has
 }
@@ -0,0 +1,408 @@
+package permission
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Blackjack200/GracticeEssential/convert"
+	"github.com/Blackjack200/GracticeEssential/util"
+	"github.com/df-mc/dragonfly/server"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+)
+
+// BanRecord is a record of a player or an IP address banned from the server.
+type BanRecord struct {
+	// Name is the name of the player banned. It is empty for bans of an XUID or an IP address.
+	Name string `json:"name,omitempty"`
+	// XUID is the XUID of the player banned. If it is not empty, the ban applies to the XUID instead of the
+	// name, so that the player cannot evade it by changing its name. It is empty for bans of an IP address,
+	// and for bans of a player whose XUID is unknown.
+	XUID string `json:"xuid,omitempty"`
+	// IP is the IP address or CIDR range banned, such as 192.168.0.0/24. It is empty for bans of a player.
+	IP string `json:"ip,omitempty"`
+	// Reason is the reason the player was banned for. It may be empty.
+	Reason string `json:"reason,omitempty"`
+	// Source is the name of the source that banned the player, such as an operator or CONSOLE.
+	Source string `json:"source"`
+	// Created is the time the player was banned at.
+	Created time.Time `json:"created"`
+	// Expires is the time the ban expires at, or nil if the ban is permanent.
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// Expired checks if the ban expired at the time passed.
+func (r BanRecord) Expired(now time.Time) bool {
+	return r.Expires != nil && !now.Before(*r.Expires)
+}
+
+// Matches checks if the ban applies to a player with the name, XUID and IP passed. Bans with an XUID are
+// matched by XUID if the XUID passed is not empty, and by name otherwise.
+func (r BanRecord) Matches(name, xuid string, ip net.IP) bool {
+	if r.IP != "" && matchAddress(r.IP, ip) {
+		return true
+	}
+	if r.XUID != "" && xuid != "" {
+		return r.XUID == xuid
+	}
+	return r.Name != "" && strings.EqualFold(r.Name, name)
+}
+
+// Message returns the message a player with the ban is disconnected with at the time passed, holding the reason
+// of the ban and the time remaining until it expires.
+func (r BanRecord) Message(now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString("You are banned from this server")
+	if r.Reason != "" {
+		sb.WriteString("\nReason: " + r.Reason)
+	}
+	if r.Expires != nil {
+		sb.WriteString("\nExpires in: " + convert.DumpDuration(r.Expires.Sub(now)))
+	}
+	return sb.String()
+}
+
+// String ...
+func (r BanRecord) String() string {
+	s := r.Name
+	if s == "" {
+		s = r.XUID
+	}
+	if s == "" {
+		s = r.IP
+	}
+	if r.Reason != "" {
+		s += fmt.Sprintf(" (%v)", r.Reason)
+	}
+	if r.Expires != nil {
+		s += fmt.Sprintf(" until %v", r.Expires.Format(time.DateTime))
+	}
+	return s
+}
+
+// key returns the key the BanRecord is stored under in a Storage.
+func (r BanRecord) key() string {
+	if r.Name != "" {
+		return "name:" + strings.ToLower(r.Name)
+	}
+	if r.XUID != "" {
+		return "xuid:" + r.XUID
+	}
+	return "ip:" + r.IP
+}
+
+// banKey returns the key of the BanRecord encoded as JSON passed.
+func banKey(value []byte) (string, error) {
+	var r BanRecord
+	if err := json.Unmarshal(value, &r); err != nil {
+		return "", err
+	}
+	return r.key(), nil
+}
+
+// BanList is a list of BanRecords stored in a Storage. Bans that expired no longer apply, and are removed
+// from the Storage when the list is next reloaded or changed.
+type BanList struct {
+	notifier
+	mu      sync.Mutex
+	storage Storage
+	records []BanRecord
+	stop    func()
+}
+
+// NewBanList returns a BanList stored as JSON in the file at the path passed. If no file exists at the path,
+// but a text file with a name per line exists at legacyPath, the names in it are migrated to the BanList as
+// permanent bans, after which the text file is renamed with a .migrated suffix. legacyPath may be empty.
+// An error is returned if the file cannot be read or holds invalid JSON.
+func NewBanList(path, legacyPath string) (*BanList, error) {
+	migrate := !util.FileExist(path) && legacyPath != "" && util.FileExist(legacyPath)
+	b, err := NewBanListWithStorage(NewJSONStorage(path, banKey))
+	if err != nil {
+		return nil, err
+	}
+	if migrate {
+		if err := b.migrate(legacyPath); err != nil {
+			_ = b.Close()
+			return nil, fmt.Errorf("migrate %v: %w", legacyPath, err)
+		}
+	}
+	return b, nil
+}
+
+// NewBanListWithStorage returns a BanList stored in the Storage passed, which must store the values of the
+// items. If the Storage implements Watcher, the BanList is reloaded and its change hooks are called when
+// another server changes it. An error is returned if the BanList cannot be loaded from the Storage.
+func NewBanListWithStorage(s Storage) (*BanList, error) {
+	b := &BanList{storage: s}
+	if err := b.Reload(); err != nil {
+		return nil, err
+	}
+	b.stop = watch(s, b.Reload, &b.notifier)
+	return b, nil
+}
+
+// Close stops watching the Storage of the BanList for changes and closes it, if it implements io.Closer.
+func (b *BanList) Close() error {
+	return closeStorage(b.storage, b.stop)
+}
+
+// migrate migrates the names in the legacy text file at the path passed to the BanList.
+func (b *BanList) migrate(legacyPath string) error {
+	data, err := os.ReadFile(legacyPath)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, name := range strings.Split(string(data), "\n") {
+		if name = strings.TrimSpace(name); name != "" {
+			if err := b.Ban(BanRecord{Name: name, Source: "migration", Created: now}); err != nil {
+				return err
+			}
+		}
+	}
+	return os.Rename(legacyPath, legacyPath+".migrated")
+}
+
+// putNoLock stores the BanRecord passed in the Storage of the BanList.
+func (b *BanList) putNoLock(r BanRecord) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return b.storage.Put(r.key(), data)
+}
+
+// Reload reads the BanList from its Storage again. If it cannot be read, the BanList is left unchanged.
+func (b *BanList) Reload() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	items, err := b.storage.Load()
+	if err != nil {
+		return err
+	}
+	records := make([]BanRecord, 0, len(items))
+	for _, item := range items {
+		var r BanRecord
+		if err := json.Unmarshal(item.Value, &r); err != nil {
+			return err
+		}
+		records = append(records, r)
+	}
+	b.records = records
+	b.pruneNoLock()
+	return nil
+}
+
+// pruneNoLock removes the bans that expired from the BanList. Bans that cannot be removed from the Storage are
+// still removed from the BanList, and are removed from the Storage when they are next loaded from it.
+func (b *BanList) pruneNoLock() {
+	now := time.Now()
+	records := b.records[:0:0]
+	for _, r := range b.records {
+		if r.Expired(now) {
+			_ = b.storage.Delete(r.key())
+		} else {
+			records = append(records, r)
+		}
+	}
+	b.records = records
+}
+
+// indexNoLock returns the index of the ban of the player with the name passed, or -1 if it is not banned.
+// Names are compared case-insensitively.
+func (b *BanList) indexNoLock(name string) int {
+	for i, r := range b.records {
+		if r.Name != "" && strings.EqualFold(r.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// xuidIndexNoLock returns the index of the ban of the XUID passed that has no name, or -1 if there is no such
+// ban.
+func (b *BanList) xuidIndexNoLock(xuid string) int {
+	for i, r := range b.records {
+		if r.Name == "" && r.XUID != "" && r.XUID == xuid {
+			return i
+		}
+	}
+	return -1
+}
+
+// addressIndexNoLock returns the index of the ban of the IP address or CIDR range passed, or -1 if it is not
+// banned.
+func (b *BanList) addressIndexNoLock(address string) int {
+	for i, r := range b.records {
+		if r.Name == "" && r.XUID == "" && r.IP == address {
+			return i
+		}
+	}
+	return -1
+}
+
+// Ban adds the BanRecord passed to the BanList, replacing an existing ban of the same player, of the same XUID
+// if the record has no name, or of the same IP address if the record has neither. If the Created time of the record is zero, it is set to the current
+// time. If the ban cannot be stored, an error is returned and the BanList is left unchanged.
+func (b *BanList) Ban(r BanRecord) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if r.Created.IsZero() {
+		r.Created = time.Now()
+	}
+	b.pruneNoLock()
+	i := b.indexNoLock(r.Name)
+	if r.Name == "" && r.XUID != "" {
+		i = b.xuidIndexNoLock(r.XUID)
+	} else if r.Name == "" {
+		i = b.addressIndexNoLock(r.IP)
+	}
+	if err := b.putNoLock(r); err != nil {
+		return err
+	}
+	if i != -1 {
+		b.records[i] = r
+	} else {
+		b.records = append(b.records, r)
+	}
+	return nil
+}
+
+// Unban removes the ban of the player with the name passed. False is returned if the player was not banned.
+// If the ban cannot be removed from the Storage, an error is returned and the BanList is left unchanged.
+func (b *BanList) Unban(name string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pruneNoLock()
+	return b.deleteNoLock(b.indexNoLock(name))
+}
+
+// UnbanXUID removes the bans of the player with the XUID passed, both bans of the XUID and bans by name that
+// hold the XUID. False is returned if the XUID was not banned. If a ban cannot be removed from the Storage, an
+// error is returned and the bans removed before are not restored.
+func (b *BanList) UnbanXUID(xuid string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pruneNoLock()
+	removed := false
+	for i := len(b.records) - 1; i >= 0; i-- {
+		if xuid == "" || b.records[i].XUID != xuid {
+			continue
+		}
+		if _, err := b.deleteNoLock(i); err != nil {
+			return removed, err
+		}
+		removed = true
+	}
+	return removed, nil
+}
+
+// UnbanAddress removes the ban of the IP address or CIDR range passed, which must be in the form returned by
+// ParseAddress. False is returned if the address was not banned. If the ban cannot be removed from the
+// Storage, an error is returned and the BanList is left unchanged.
+func (b *BanList) UnbanAddress(address string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pruneNoLock()
+	return b.deleteNoLock(b.addressIndexNoLock(address))
+}
+
+// deleteNoLock removes the ban at the index passed, which may be -1 if there is no ban to remove.
+func (b *BanList) deleteNoLock(i int) (bool, error) {
+	if i == -1 {
+		return false, nil
+	}
+	if err := b.storage.Delete(b.records[i].key()); err != nil {
+		return false, err
+	}
+	b.records = append(b.records[:i:i], b.records[i+1:]...)
+	return true, nil
+}
+
+// Match returns the ban that applies to a player with the name, XUID and IP passed, if any. Bans that expired
+// are ignored.
+func (b *BanList) Match(name, xuid string, ip net.IP) (BanRecord, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	for _, r := range b.records {
+		if !r.Expired(now) && r.Matches(name, xuid, ip) {
+			return r, true
+		}
+	}
+	return BanRecord{}, false
+}
+
+// Record returns the ban of the player with the name passed, if it is banned.
+func (b *BanList) Record(name string) (BanRecord, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if i := b.indexNoLock(name); i != -1 && !b.records[i].Expired(time.Now()) {
+		return b.records[i], true
+	}
+	return BanRecord{}, false
+}
+
+// Records returns all bans in the BanList that have not expired.
+func (b *BanList) Records() []BanRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	records := make([]BanRecord, 0, len(b.records))
+	for _, r := range b.records {
+		if !r.Expired(now) {
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+// GetAll returns the names of all players banned.
+func (b *BanList) GetAll() []string {
+	var names []string
+	for _, r := range b.Records() {
+		if r.Name != "" {
+			names = append(names, r.Name)
+		}
+	}
+	return names
+}
+
+// Has checks if the player with the name passed is banned.
+func (b *BanList) Has(name string) bool {
+	_, ok := b.Record(name)
+	return ok
+}
+
+// Add bans the player with the name passed permanently, without a reason.
+func (b *BanList) Add(name string) error {
+	return b.Ban(BanRecord{Name: name})
+}
+
+// Delete removes the ban of the player with the name passed.
+func (b *BanList) Delete(name string) error {
+	_, err := b.Unban(name)
+	return err
+}
+
+// ServerAllower returns a server.Allower that disallows banned players from joining, by name, XUID or IP,
+// with a message holding the reason of the ban and the time remaining until it expires.
+func (b *BanList) ServerAllower() server.Allower {
+	return banServerAllower{b: b}
+}
+
+type banServerAllower struct {
+	b *BanList
+}
+
+func (a banServerAllower) Allow(addr net.Addr, d login.IdentityData, _ login.ClientData) (string, bool) {
+	remember(addr, d)
+	if r, ok := a.b.Match(d.DisplayName, d.XUID, IPOf(addr)); ok {
+		return r.Message(time.Now()), false
+	}
+	return "", true
+}
@@ -0,0 +1,186 @@
+package permission
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+)
+
+func TestNewBanListInvalidJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "banned-players.json")
+	if err := os.WriteFile(path, []byte(`[{"name": "Steve",`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewBanList(path, ""); err == nil {
+		t.Fatal("expected an error for a ban list holding invalid JSON")
+	}
+}
+
+func TestNewBanListMigrate(t *testing.T) {
+	dir := t.TempDir()
+	path, legacyPath := filepath.Join(dir, "banned-players.json"), filepath.Join(dir, "banned-players.txt")
+	if err := os.WriteFile(legacyPath, []byte("Steve\n\nAlex\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewBanList(path, legacyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	if !b.Has("steve") || !b.Has("Alex") || len(b.Records()) != 2 {
+		t.Fatalf("expected Steve and Alex to be banned, got %v", b.Records())
+	}
+	if _, err := os.Stat(legacyPath + ".migrated"); err != nil {
+		t.Fatalf("expected the legacy ban list to be renamed: %v", err)
+	}
+}
+
+func TestBanRecordMatches(t *testing.T) {
+	tests := []struct {
+		r              BanRecord
+		name, xuid, ip string
+		want           bool
+	}{
+		{BanRecord{Name: "Steve"}, "steve", "", "", true},
+		{BanRecord{Name: "Steve"}, "Alex", "", "", false},
+		{BanRecord{Name: "Steve", XUID: "1"}, "Steve", "2", "", false},
+		{BanRecord{Name: "Steve", XUID: "1"}, "Alex", "1", "", true},
+		{BanRecord{Name: "Steve", XUID: "1"}, "steve", "", "", true},
+		{BanRecord{XUID: "1"}, "Steve", "1", "", true},
+		{BanRecord{XUID: "1"}, "Steve", "", "", false},
+		{BanRecord{IP: "192.168.0.1"}, "Steve", "", "192.168.0.1", true},
+		{BanRecord{IP: "192.168.0.1"}, "Steve", "", "192.168.0.2", false},
+		{BanRecord{IP: "192.168.0.0/24"}, "Steve", "", "192.168.0.42", true},
+		{BanRecord{IP: "192.168.0.0/24"}, "Steve", "", "192.168.1.1", false},
+		{BanRecord{IP: "192.168.0.0/24"}, "", "", "", false},
+		{BanRecord{IP: "::1"}, "", "", "0:0::1", true},
+	}
+	for _, test := range tests {
+		if got := test.r.Matches(test.name, test.xuid, net.ParseIP(test.ip)); got != test.want {
+			t.Errorf("%+v.Matches(%q, %q, %q) = %v, expected %v", test.r, test.name, test.xuid, test.ip, got, test.want)
+		}
+	}
+}
+
+func TestRememberBounded(t *testing.T) {
+	defer func(n int) {
+		MaxIdentities = n
+	}(MaxIdentities)
+	MaxIdentities = 10
+
+	addr := &net.UDPAddr{IP: net.ParseIP("192.168.0.1"), Port: 19132}
+	remember(addr, login.IdentityData{DisplayName: "Steve", XUID: "1"})
+	for i := 0; i < 100; i++ {
+		remember(addr, login.IdentityData{DisplayName: fmt.Sprint("Player", i)})
+		// Steve joins again every few logins, so it must not be forgotten.
+		if i%5 == 0 {
+			remember(addr, login.IdentityData{DisplayName: "STEVE", XUID: "1"})
+		}
+	}
+	if n := len(identities.names); n != MaxIdentities {
+		t.Fatalf("expected %v identities to be remembered, got %v", MaxIdentities, n)
+	}
+	if _, ok := LastIdentity("Player0"); ok {
+		t.Fatal("expected the oldest identity to be forgotten")
+	}
+	if id, ok := LastIdentity("player99"); !ok || !id.IP.Equal(addr.IP) {
+		t.Fatalf("expected the newest identity to be remembered, got %+v", id)
+	}
+	if id, ok := LastIdentity("steve"); !ok || id.Name != "STEVE" || id.XUID != "1" {
+		t.Fatalf("expected the last identity of Steve to be remembered, got %+v", id)
+	}
+}
+
+// countingStorage is a Storage that counts the changes made to it.
+type countingStorage struct {
+	Storage
+	changes int
+}
+
+func (s *countingStorage) Put(key string, value []byte) error {
+	s.changes++
+	return s.Storage.Put(key, value)
+}
+
+func (s *countingStorage) Delete(key string) error {
+	s.changes++
+	return s.Storage.Delete(key)
+}
+
+func TestBanListXUID(t *testing.T) {
+	b, err := NewBanListWithStorage(NewJSONStorage(filepath.Join(t.TempDir(), "banned-players.json"), banKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	for _, r := range []BanRecord{{XUID: "1"}, {XUID: "2"}, {Name: "Steve", XUID: "3"}, {IP: "192.168.0.1"}} {
+		if err := b.Ban(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(b.Records()); n != 4 {
+		t.Fatalf("expected the bans of different XUIDs not to replace each other, got %v", b.Records())
+	}
+	if _, ok := b.Match("Alex", "2", nil); !ok {
+		t.Fatal("expected a ban of an XUID to apply to a player with the XUID")
+	}
+	if ok, err := b.UnbanXUID("3"); err != nil || !ok {
+		t.Fatalf("expected the ban of Steve to be removed by its XUID, got %v, %v", ok, err)
+	}
+	if ok, err := b.UnbanXUID("2"); err != nil || !ok {
+		t.Fatalf("expected the ban of XUID 2 to be removed, got %v, %v", ok, err)
+	}
+	if ok, _ := b.UnbanXUID("2"); ok {
+		t.Fatal("expected no ban to be removed for an XUID that is not banned")
+	}
+	if _, ok := b.Match("Steve", "3", nil); ok {
+		t.Fatal("expected Steve to be unbanned")
+	}
+	if _, ok := b.Match("Alex", "1", nil); !ok {
+		t.Fatal("expected XUID 1 to still be banned")
+	}
+}
+
+func TestBanListMatchReadOnly(t *testing.T) {
+	s := &countingStorage{Storage: NewJSONStorage(filepath.Join(t.TempDir(), "banned-players.json"), banKey)}
+	b, err := NewBanListWithStorage(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	expired := time.Now().Add(-time.Minute)
+	for _, r := range []BanRecord{{Name: "Steve"}, {Name: "Alex", Expires: &expired}} {
+		if err := b.Ban(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.changes = 0
+
+	if r, ok := b.Match("Steve", "1", nil); !ok || r.XUID != "" {
+		t.Fatalf("expected the ban of Steve to match without an XUID being added, got %+v, %v", r, ok)
+	}
+	if _, ok := b.Match("Steve", "2", nil); !ok {
+		t.Fatal("expected the ban of Steve to match another player named Steve")
+	}
+	if _, ok := b.Match("Alex", "", nil); ok {
+		t.Fatal("expected an expired ban not to match")
+	}
+	if b.Has("Alex") || len(b.Records()) != 1 {
+		t.Fatalf("expected only the ban of Steve to be listed, got %v", b.Records())
+	}
+	if s.changes != 0 {
+		t.Fatalf("expected looking up bans not to change the storage, got %v changes", s.changes)
+	}
+
+	if err := b.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if items, _ := s.Load(); len(items) != 1 {
+		t.Fatalf("expected the expired ban to be removed on reload, got %v", keys(items))
+	}
+}
@@ -1,36 +1,35 @@
 package permission
 
 import (
+	"net"
 	"strings"
 	"sync"
-
-	"github.com/Blackjack200/GracticeEssential/util"
 )
 
 type Entry struct {
-	mu     sync.Mutex
-	path   string
-	list   []string
-	except string
-}
-
-func (e *Entry) write() {
-	util.MustWriteFile(e.path, []byte(strings.Join(e.list, "\n")))
+	notifier
+	mu      sync.Mutex
+	storage Storage
+	list    []string
+	stop    func()
 }
 
-func (e *Entry) Reload() {
+// Reload reads the Entry from its Storage again. If it cannot be read, the Entry is left unchanged.
+func (e *Entry) Reload() error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
-	if !util.FileExist(e.path) {
-		util.MustWriteFile(e.path, nil)
+	items, err := e.storage.Load()
+	if err != nil {
+		return err
 	}
 	var s []string
-	for _, a := range strings.Split(string(util.MustReadFile(e.path)), "\n") {
-		if len(strings.TrimSpace(a)) != 0 {
-			s = append(s, a)
+	for _, item := range items {
+		if len(strings.TrimSpace(item.Key)) != 0 {
+			s = append(s, item.Key)
 		}
 	}
 	e.list = s
+	return nil
 }
 
 func (e *Entry) GetAll() []string {
@@ -48,30 +47,53 @@ func (e *Entry) Has(n string) bool {
 }
 
 func (e *Entry) hasNoLock(n string) bool {
-	if n == e.except {
+	for _, l := range e.list {
+		if l == n {
+			return true
+		}
+	}
+	return false
+}
+
+// Match checks if the Entry has the name or the XUID passed, or an IP address or CIDR range that the IP
+// passed is in.
+func (e *Entry) Match(name, xuid string, ip net.IP) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.hasNoLock(name) {
 		return true
 	}
 	for _, l := range e.list {
-		if l == n {
+		if (xuid != "" && l == xuid) || matchAddress(l, ip) {
 			return true
 		}
 	}
 	return false
 }
 
-func (e *Entry) Add(n string) {
+// Add adds the name passed to the Entry. If it cannot be stored, an error is returned and the Entry is left
+// unchanged.
+func (e *Entry) Add(n string) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	if !e.hasNoLock(n) {
+		if err := e.storage.Put(n, nil); err != nil {
+			return err
+		}
 		e.list = append(e.list, n)
-		e.write()
 	}
+	return nil
 }
 
-func (e *Entry) Delete(n string) {
+// Delete removes the name passed from the Entry. If it cannot be removed from the Storage, an error is
+// returned and the Entry is left unchanged.
+func (e *Entry) Delete(n string) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	if e.hasNoLock(n) {
+		if err := e.storage.Delete(n); err != nil {
+			return err
+		}
 		var a []string
 		for _, l := range e.list {
 			if l != n {
@@ -79,17 +101,33 @@ func (e *Entry) Delete(n string) {
 			}
 		}
 		e.list = a
-		e.write()
 	}
+	return nil
 }
 
-func NewEntry(path string, expect string) *Entry {
+// NewEntry returns an Entry stored one per line in the text file at the path passed. An error is returned if
+// the file cannot be read.
+func NewEntry(path string) (*Entry, error) {
+	return NewEntryWithStorage(NewTextStorage(path))
+}
+
+// NewEntryWithStorage returns an Entry stored in the Storage passed. If the Storage implements Watcher, the
+// Entry is reloaded and its change hooks are called when another server changes it. An error is returned if
+// the Entry cannot be loaded from the Storage.
+func NewEntryWithStorage(s Storage) (*Entry, error) {
 	e := &Entry{
-		mu:     sync.Mutex{},
-		path:   path,
-		list:   nil,
-		except: expect,
+		mu:      sync.Mutex{},
+		storage: s,
+		list:    nil,
+	}
+	if err := e.Reload(); err != nil {
+		return nil, err
 	}
-	e.Reload()
-	return e
+	e.stop = watch(s, e.Reload, &e.notifier)
+	return e, nil
+}
+
+// Close stops watching the Storage of the Entry for changes and closes it, if it implements io.Closer.
+func (e *Entry) Close() error {
+	return closeStorage(e.storage, e.stop)
 }
@@ -0,0 +1,599 @@
+package permission
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Blackjack200/GracticeEssential/util"
+)
+
+const (
+	// DefaultGroup is the group every player is a member of.
+	DefaultGroup = "default"
+	// OperatorGroup is the group the players in the ops Entry of Permissions are a member of.
+	OperatorGroup = "op"
+)
+
+// Group is a group of permissions that players may be a member of. A group inherits the permissions of the
+// groups in Inherits, but permissions set in the group itself take precedence over them.
+type Group struct {
+	Inherits    []string        `json:"inherits,omitempty"`
+	Permissions map[string]bool `json:"permissions,omitempty"`
+}
+
+// PlayerPermissions are the groups and permissions of a player. Permissions set for the player take precedence
+// over those of its groups.
+type PlayerPermissions struct {
+	Groups      []string        `json:"groups,omitempty"`
+	Permissions map[string]bool `json:"permissions,omitempty"`
+}
+
+type permissionData struct {
+	Groups  map[string]*Group             `json:"groups"`
+	Players map[string]*PlayerPermissions `json:"players"`
+}
+
+// Permissions holds the permission nodes granted to and denied from groups and players, stored as JSON in a
+// file. Nodes are dot separated names, such as essential.command.kick. A permission set for a node ending in
+// .* applies to all nodes starting with it, and a permission set for * applies to all nodes. If permissions
+// for several matching nodes are set, the most specific one applies, with denials taking precedence over
+// grants that are as specific.
+// A player has a permission if it was granted to the player, and otherwise if it was granted to the first of
+// its groups, in the order they were added, followed by OperatorGroup for operators and DefaultGroup, that
+// grants or denies it.
+type Permissions struct {
+	mu   sync.Mutex
+	path string
+	data permissionData
+	ops  *Entry
+	stop func()
+	// last is the content of the file last read or written, so that writes of the Permissions themselves do
+	// not make them reload.
+	last []byte
+}
+
+// NewPermissions returns Permissions stored at the path passed. Players in the ops Entry passed, which may be
+// nil, are members of OperatorGroup. If no file exists at the path, it is created with an empty DefaultGroup
+// and an OperatorGroup that is granted all permissions.
+// The file is watched for changes, such as those made by hand, after which the Permissions are reloaded. If the
+// file cannot be read or holds invalid JSON, the Permissions are left unchanged. If this is the case when
+// NewPermissions is called, an error is returned.
+func NewPermissions(path string, ops *Entry) (*Permissions, error) {
+	p := &Permissions{path: path, ops: ops}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	p.stop = watchFile(&p.mu, path, &p.last, func() {
+		_ = p.Reload()
+	})
+	return p, nil
+}
+
+// Close stops watching the file of the Permissions for changes.
+func (p *Permissions) Close() error {
+	p.stop()
+	return nil
+}
+
+func (p *Permissions) write() error {
+	b, err := json.MarshalIndent(p.data, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := util.WriteFile(p.path, b); err != nil {
+		return err
+	}
+	p.last = b
+	return nil
+}
+
+// saveNoLock writes the Permissions to their file after a change. If they cannot be written, the Permissions
+// are reverted to the data passed, which is a clone of the data before the change, and an error is returned.
+func (p *Permissions) saveNoLock(prev permissionData) error {
+	if err := p.write(); err != nil {
+		p.data = prev
+		return fmt.Errorf("write permissions: %w", err)
+	}
+	return nil
+}
+
+// clone returns a deep copy of the permissionData.
+func (d permissionData) clone() permissionData {
+	c := permissionData{Groups: make(map[string]*Group, len(d.Groups)), Players: make(map[string]*PlayerPermissions, len(d.Players))}
+	for name, g := range d.Groups {
+		c.Groups[name] = &Group{Inherits: append([]string(nil), g.Inherits...), Permissions: copyPermissions(g.Permissions)}
+	}
+	for name, pp := range d.Players {
+		c.Players[name] = &PlayerPermissions{Groups: append([]string(nil), pp.Groups...), Permissions: copyPermissions(pp.Permissions)}
+	}
+	return c
+}
+
+// Reload reads the Permissions from their file again, creating the file if it does not exist. If the file
+// cannot be read or holds invalid JSON, the Permissions are left unchanged and an error is returned.
+func (p *Permissions) Reload() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !util.FileExist(p.path) {
+		prev := p.data
+		p.data = permissionData{}.normalize()
+		if err := p.saveNoLock(prev); err != nil {
+			return err
+		}
+	} else {
+		b, err := os.ReadFile(p.path)
+		if err != nil {
+			return err
+		}
+		var data permissionData
+		if len(strings.TrimSpace(string(b))) != 0 {
+			if err := json.Unmarshal(b, &data); err != nil {
+				return err
+			}
+		}
+		p.data, p.last = data.normalize(), b
+	}
+	return nil
+}
+
+// normalize returns the permissionData with the names of groups and players and the permission nodes
+// lowercased, as they are looked up case-insensitively, merging those that only differ in case. DefaultGroup
+// and OperatorGroup are added if they are missing, the latter being granted all permissions, so that an edit
+// of the file made by hand cannot leave operators without any permissions. They are written to the file with
+// the next change.
+func (d permissionData) normalize() permissionData {
+	n := permissionData{Groups: make(map[string]*Group, len(d.Groups)), Players: make(map[string]*PlayerPermissions, len(d.Players))}
+	for name, g := range d.Groups {
+		name = strings.ToLower(name)
+		ng, ok := n.Groups[name]
+		if !ok {
+			ng = &Group{}
+			n.Groups[name] = ng
+		}
+		if g != nil {
+			ng.Inherits = appendLower(ng.Inherits, g.Inherits)
+			ng.Permissions = mergeLower(ng.Permissions, g.Permissions)
+		}
+	}
+	for name, pp := range d.Players {
+		if pp == nil {
+			continue
+		}
+		name = strings.ToLower(name)
+		np, ok := n.Players[name]
+		if !ok {
+			np = &PlayerPermissions{}
+			n.Players[name] = np
+		}
+		np.Groups = appendLower(np.Groups, pp.Groups)
+		np.Permissions = mergeLower(np.Permissions, pp.Permissions)
+	}
+	if _, ok := n.Groups[DefaultGroup]; !ok {
+		n.Groups[DefaultGroup] = &Group{}
+	}
+	if _, ok := n.Groups[OperatorGroup]; !ok {
+		n.Groups[OperatorGroup] = &Group{Permissions: map[string]bool{"*": true}}
+	}
+	return n
+}
+
+// appendLower appends the lowercased names passed to s, skipping those already in it.
+func appendLower(s, names []string) []string {
+	for _, name := range names {
+		if name = strings.ToLower(name); !slices.Contains(s, name) {
+			s = append(s, name)
+		}
+	}
+	return s
+}
+
+// mergeLower sets the permissions passed in perms, which may be nil, with their nodes lowercased, and returns
+// perms. If two nodes only differ in case, denials take precedence.
+func mergeLower(perms, src map[string]bool) map[string]bool {
+	for node, v := range src {
+		if perms == nil {
+			perms = make(map[string]bool, len(src))
+		}
+		node = strings.ToLower(node)
+		if prev, ok := perms[node]; ok {
+			v = v && prev
+		}
+		perms[node] = v
+	}
+	return perms
+}
+
+// ValidateNode checks if the node passed is a valid permission node.
+func ValidateNode(node string) error {
+	if node == "*" {
+		return nil
+	}
+	for i, part := range strings.Split(node, ".") {
+		if part == "" || strings.ContainsAny(part, " \t\n") || (part == "*" && i != strings.Count(node, ".")) {
+			return fmt.Errorf("invalid permission node %q", node)
+		}
+	}
+	return nil
+}
+
+// lookup returns the permission the map passed holds for the node passed, and whether it holds any.
+func lookup(perms map[string]bool, node string) (value bool, ok bool) {
+	best := -1
+	for k, v := range perms {
+		var specificity int
+		switch {
+		case k == node:
+			specificity = len(k) + 1
+		case k == "*":
+			specificity = 0
+		case strings.HasSuffix(k, ".*") && strings.HasPrefix(node, k[:len(k)-1]):
+			specificity = len(k) - 1
+		default:
+			continue
+		}
+		if specificity > best || (specificity == best && !v) {
+			best, value = specificity, v
+		}
+	}
+	return value, best != -1
+}
+
+// SetOperators changes the Entry of which the players are members of OperatorGroup. ops may be nil.
+func (p *Permissions) SetOperators(ops *Entry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ops = ops
+}
+
+// Has checks if the player with the name passed has the permission node passed.
+func (p *Permissions) Has(name, node string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	node = strings.ToLower(node)
+	if pp, ok := p.data.Players[strings.ToLower(name)]; ok {
+		if v, ok := lookup(pp.Permissions, node); ok {
+			return v
+		}
+	}
+	for _, g := range p.groupsNoLock(name) {
+		if v, ok := p.groupLookupNoLock(g, node, map[string]struct{}{}); ok {
+			return v
+		}
+	}
+	return false
+}
+
+// groupLookupNoLock returns the permission the group passed or the groups it inherits hold for the node
+// passed, and whether they hold any.
+func (p *Permissions) groupLookupNoLock(group, node string, visited map[string]struct{}) (bool, bool) {
+	if _, ok := visited[group]; ok {
+		return false, false
+	}
+	visited[group] = struct{}{}
+	g, ok := p.data.Groups[group]
+	if !ok {
+		return false, false
+	}
+	if v, ok := lookup(g.Permissions, node); ok {
+		return v, true
+	}
+	for _, parent := range g.Inherits {
+		if v, ok := p.groupLookupNoLock(parent, node, visited); ok {
+			return v, true
+		}
+	}
+	return false, false
+}
+
+// groupsNoLock returns the groups of the player with the name passed in the order they are consulted.
+func (p *Permissions) groupsNoLock(name string) []string {
+	var groups []string
+	if pp, ok := p.data.Players[strings.ToLower(name)]; ok {
+		groups = append(groups, pp.Groups...)
+	}
+	if p.ops != nil && p.ops.Has(name) {
+		groups = append(groups, OperatorGroup)
+	}
+	return append(groups, DefaultGroup)
+}
+
+// PlayerGroups returns the groups of the player with the name passed in the order they are consulted,
+// including OperatorGroup for operators and DefaultGroup.
+func (p *Permissions) PlayerGroups(name string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.groupsNoLock(name)
+}
+
+// Player returns the groups and permissions set for the player with the name passed.
+func (p *Permissions) Player(name string) PlayerPermissions {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pp, ok := p.data.Players[strings.ToLower(name)]
+	if !ok {
+		return PlayerPermissions{}
+	}
+	return PlayerPermissions{Groups: append([]string(nil), pp.Groups...), Permissions: copyPermissions(pp.Permissions)}
+}
+
+// Groups returns the sorted names of all groups.
+func (p *Permissions) Groups() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	names := make([]string, 0, len(p.data.Groups))
+	for name := range p.data.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Group returns the group with the name passed, if it exists.
+func (p *Permissions) Group(name string) (Group, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	g, ok := p.data.Groups[strings.ToLower(name)]
+	if !ok {
+		return Group{}, false
+	}
+	return Group{Inherits: append([]string(nil), g.Inherits...), Permissions: copyPermissions(g.Permissions)}, true
+}
+
+// CreateGroup creates an empty group with the name passed. An error is returned if the group already exists.
+func (p *Permissions) CreateGroup(name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	name = strings.ToLower(name)
+	if name == "" {
+		return fmt.Errorf("group name must not be empty")
+	}
+	if _, ok := p.data.Groups[name]; ok {
+		return fmt.Errorf("group %v already exists", name)
+	}
+	prev := p.data.clone()
+	p.data.Groups[name] = &Group{}
+	return p.saveNoLock(prev)
+}
+
+// DeleteGroup deletes the group with the name passed and removes it from the players and groups it was added
+// to. DefaultGroup and OperatorGroup cannot be deleted.
+func (p *Permissions) DeleteGroup(name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	name = strings.ToLower(name)
+	if _, ok := p.data.Groups[name]; !ok {
+		return fmt.Errorf("group %v does not exist", name)
+	}
+	if name == DefaultGroup || name == OperatorGroup {
+		return fmt.Errorf("group %v cannot be deleted", name)
+	}
+	prev := p.data.clone()
+	delete(p.data.Groups, name)
+	for _, g := range p.data.Groups {
+		g.Inherits = without(g.Inherits, name)
+	}
+	for _, pp := range p.data.Players {
+		pp.Groups = without(pp.Groups, name)
+	}
+	return p.saveNoLock(prev)
+}
+
+// SetGroupPermission grants or denies the permission node passed to the group passed.
+func (p *Permissions) SetGroupPermission(group, node string, value bool) error {
+	if err := ValidateNode(node); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	g, ok := p.data.Groups[strings.ToLower(group)]
+	if !ok {
+		return fmt.Errorf("group %v does not exist", group)
+	}
+	prev := p.data.clone()
+	if g.Permissions == nil {
+		g.Permissions = map[string]bool{}
+	}
+	g.Permissions[strings.ToLower(node)] = value
+	return p.saveNoLock(prev)
+}
+
+// UnsetGroupPermission removes the permission node passed from the group passed, so that it is inherited
+// again. An error is returned if the node was not set.
+func (p *Permissions) UnsetGroupPermission(group, node string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	g, ok := p.data.Groups[strings.ToLower(group)]
+	if !ok {
+		return fmt.Errorf("group %v does not exist", group)
+	}
+	if _, ok := g.Permissions[strings.ToLower(node)]; !ok {
+		return fmt.Errorf("permission %v is not set for group %v", node, group)
+	}
+	prev := p.data.clone()
+	delete(g.Permissions, strings.ToLower(node))
+	return p.saveNoLock(prev)
+}
+
+// AddParent makes the group passed inherit the permissions of the parent group passed.
+func (p *Permissions) AddParent(group, parent string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	group, parent = strings.ToLower(group), strings.ToLower(parent)
+	g, ok := p.data.Groups[group]
+	if !ok {
+		return fmt.Errorf("group %v does not exist", group)
+	}
+	if _, ok := p.data.Groups[parent]; !ok {
+		return fmt.Errorf("group %v does not exist", parent)
+	}
+	if p.inheritsNoLock(parent, group, map[string]struct{}{}) {
+		return fmt.Errorf("group %v already inherits from %v", parent, group)
+	}
+	for _, i := range g.Inherits {
+		if i == parent {
+			return fmt.Errorf("group %v already inherits from %v", group, parent)
+		}
+	}
+	prev := p.data.clone()
+	g.Inherits = append(g.Inherits, parent)
+	return p.saveNoLock(prev)
+}
+
+// RemoveParent makes the group passed no longer inherit the permissions of the parent group passed. An error
+// is returned if it did not inherit them.
+func (p *Permissions) RemoveParent(group, parent string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	g, ok := p.data.Groups[strings.ToLower(group)]
+	if !ok {
+		return fmt.Errorf("group %v does not exist", group)
+	}
+	inherits := without(g.Inherits, strings.ToLower(parent))
+	if len(inherits) == len(g.Inherits) {
+		return fmt.Errorf("group %v does not inherit from %v", group, parent)
+	}
+	prev := p.data.clone()
+	g.Inherits = inherits
+	return p.saveNoLock(prev)
+}
+
+// inheritsNoLock checks if the group passed is or inherits, directly or indirectly, from the parent passed.
+func (p *Permissions) inheritsNoLock(group, parent string, visited map[string]struct{}) bool {
+	if group == parent {
+		return true
+	}
+	if _, ok := visited[group]; ok {
+		return false
+	}
+	visited[group] = struct{}{}
+	if g, ok := p.data.Groups[group]; ok {
+		for _, i := range g.Inherits {
+			if p.inheritsNoLock(i, parent, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// playerNoLock returns the PlayerPermissions of the player with the name passed, creating it if needed.
+func (p *Permissions) playerNoLock(name string) *PlayerPermissions {
+	name = strings.ToLower(name)
+	pp, ok := p.data.Players[name]
+	if !ok {
+		pp = &PlayerPermissions{}
+		p.data.Players[name] = pp
+	}
+	return pp
+}
+
+// cleanNoLock removes the PlayerPermissions of the player with the name passed if it holds nothing.
+func (p *Permissions) cleanNoLock(name string) {
+	name = strings.ToLower(name)
+	if pp, ok := p.data.Players[name]; ok && len(pp.Groups) == 0 && len(pp.Permissions) == 0 {
+		delete(p.data.Players, name)
+	}
+}
+
+// AddPlayerGroup adds the player with the name passed to the group passed.
+func (p *Permissions) AddPlayerGroup(name, group string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	group = strings.ToLower(group)
+	if _, ok := p.data.Groups[group]; !ok {
+		return fmt.Errorf("group %v does not exist", group)
+	}
+	if group == DefaultGroup {
+		return fmt.Errorf("every player is a member of group %v", group)
+	}
+	prev := p.data.clone()
+	pp := p.playerNoLock(name)
+	for _, g := range pp.Groups {
+		if g == group {
+			p.data = prev
+			return fmt.Errorf("player %v is already a member of group %v", name, group)
+		}
+	}
+	pp.Groups = append(pp.Groups, group)
+	return p.saveNoLock(prev)
+}
+
+// RemovePlayerGroup removes the player with the name passed from the group passed. An error is returned if
+// the player was not added to the group.
+func (p *Permissions) RemovePlayerGroup(name, group string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var groups []string
+	pp, ok := p.data.Players[strings.ToLower(name)]
+	if ok {
+		groups = without(pp.Groups, strings.ToLower(group))
+	}
+	if !ok || len(groups) == len(pp.Groups) {
+		return fmt.Errorf("player %v is not a member of group %v", name, group)
+	}
+	prev := p.data.clone()
+	pp.Groups = groups
+	p.cleanNoLock(name)
+	return p.saveNoLock(prev)
+}
+
+// SetPlayerPermission grants or denies the permission node passed to the player with the name passed.
+func (p *Permissions) SetPlayerPermission(name, node string, value bool) error {
+	if err := ValidateNode(node); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	prev := p.data.clone()
+	pp := p.playerNoLock(name)
+	if pp.Permissions == nil {
+		pp.Permissions = map[string]bool{}
+	}
+	pp.Permissions[strings.ToLower(node)] = value
+	return p.saveNoLock(prev)
+}
+
+// UnsetPlayerPermission removes the permission node passed from the player with the name passed, so that it
+// is taken from its groups again. An error is returned if the node was not set.
+func (p *Permissions) UnsetPlayerPermission(name, node string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pp, ok := p.data.Players[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("permission %v is not set for player %v", node, name)
+	}
+	if _, ok := pp.Permissions[strings.ToLower(node)]; !ok {
+		return fmt.Errorf("permission %v is not set for player %v", node, name)
+	}
+	prev := p.data.clone()
+	delete(pp.Permissions, strings.ToLower(node))
+	p.cleanNoLock(name)
+	return p.saveNoLock(prev)
+}
+
+func copyPermissions(perms map[string]bool) map[string]bool {
+	if perms == nil {
+		return nil
+	}
+	m := make(map[string]bool, len(perms))
+	for k, v := range perms {
+		m[k] = v
+	}
+	return m
+}
+
+// without returns the slice passed without the value passed.
+func without(s []string, v string) []string {
+	var a []string
+	for _, e := range s {
+		if e != v {
+			a = append(a, e)
+		}
+	}
+	return a
+}
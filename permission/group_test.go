@@ -0,0 +1,188 @@
+package permission
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewPermissionsInvalidJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "permissions.json")
+	if err := os.WriteFile(path, []byte(`{"groups": `), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPermissions(path, nil); err == nil {
+		t.Fatal("expected an error for permissions holding invalid JSON")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	perms := map[string]bool{
+		"*":                        true,
+		"essential.command.*":      false,
+		"essential.command.kick":   true,
+		"essential.command.ban":    true,
+		"essential.command.ban.ip": false,
+	}
+	tests := []struct {
+		node      string
+		value, ok bool
+	}{
+		{"essential.command.kick", true, true},
+		{"essential.command.stop", false, true},
+		{"essential.command.ban.ip", false, true},
+		{"essential.command.ban", true, true},
+		{"other.node", true, true},
+	}
+	for _, test := range tests {
+		if v, ok := lookup(perms, test.node); v != test.value || ok != test.ok {
+			t.Errorf("lookup(%q) = %v, %v, expected %v, %v", test.node, v, ok, test.value, test.ok)
+		}
+	}
+	if _, ok := lookup(map[string]bool{"essential.command.*": true}, "essential.commands"); ok {
+		t.Error("expected essential.command.* not to match essential.commands")
+	}
+	if _, ok := lookup(nil, "essential.command.kick"); ok {
+		t.Error("expected no permission in an empty map")
+	}
+}
+
+// newPermissions returns Permissions stored in a file holding the JSON passed, with the ops passed.
+func newPermissions(t *testing.T, data string, ops ...string) *Permissions {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "permissions.json")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e, err := NewEntry(filepath.Join(dir, "ops.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = e.Close()
+	})
+	for _, op := range ops {
+		if err := e.Add(op); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p, err := NewPermissions(path, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = p.Close()
+	})
+	return p
+}
+
+func TestPermissionsHas(t *testing.T) {
+	p := newPermissions(t, `{
+		"groups": {
+			"default": {"permissions": {"essential.command.list": true}},
+			"op": {"permissions": {"*": true}},
+			"mod": {"inherits": ["helper"], "permissions": {"essential.command.kick": true}},
+			"helper": {"permissions": {"essential.command.kick": false, "essential.command.ban": true}},
+			"muted": {"permissions": {"essential.command.ban": false}}
+		},
+		"players": {
+			"steve": {"groups": ["mod"], "permissions": {"essential.command.stop": true}},
+			"alex": {"groups": ["muted", "mod"]},
+			"notch": {"permissions": {"essential.command.stop": false}}
+		}
+	}`, "Notch")
+
+	tests := []struct {
+		name, node string
+		want       bool
+	}{
+		{"Steve", "essential.command.stop", true},
+		{"Steve", "essential.command.kick", true},
+		{"Steve", "essential.command.ban", true},
+		{"Steve", "essential.command.list", true},
+		{"Steve", "essential.command.gc", false},
+		{"Alex", "essential.command.ban", false},
+		{"Alex", "essential.command.kick", true},
+		{"Notch", "essential.command.gc", true},
+		{"Notch", "essential.command.stop", false},
+		{"Herobrine", "essential.command.list", true},
+		{"Herobrine", "essential.command.kick", false},
+	}
+	for _, test := range tests {
+		if got := p.Has(test.name, test.node); got != test.want {
+			t.Errorf("Has(%q, %q) = %v, expected %v", test.name, test.node, got, test.want)
+		}
+	}
+}
+
+func TestPermissionsInheritCycle(t *testing.T) {
+	p := newPermissions(t, `{"groups": {"a": {"inherits": ["b"]}, "b": {"inherits": ["a"]}}, "players": {"steve": {"groups": ["a"]}}}`)
+	if p.Has("Steve", "essential.command.kick") {
+		t.Fatal("expected no permission from groups inheriting from each other")
+	}
+	if err := p.AddParent("b", "a"); err == nil {
+		t.Fatal("expected an error when adding a parent that is already inherited")
+	}
+}
+
+func TestPermissionsNormalize(t *testing.T) {
+	p := newPermissions(t, `{
+		"groups": {"Mod": {"inherits": ["Helper"], "permissions": {"Essential.Command.Kick": true}}, "helper": {}},
+		"players": {"Steve": {"groups": ["MOD"]}, "steve": {"permissions": {"Essential.Command.Ban": true}}}
+	}`, "Notch")
+
+	if !p.Has("steve", "essential.command.kick") || !p.Has("STEVE", "Essential.Command.Ban") {
+		t.Fatal("expected names and nodes with uppercase letters in the file to match")
+	}
+	if g, ok := p.Group("mod"); !ok || len(g.Inherits) != 1 || g.Inherits[0] != "helper" {
+		t.Fatalf("expected group mod to inherit from helper, got %+v", g)
+	}
+	if _, ok := p.Group(DefaultGroup); !ok {
+		t.Fatal("expected the default group to be added")
+	}
+	if !p.Has("Notch", "essential.command.stop") {
+		t.Fatal("expected operators to be granted all permissions if the op group is missing")
+	}
+}
+
+func TestPermissionsMutators(t *testing.T) {
+	p := newPermissions(t, ``)
+	if err := p.CreateGroup("Mod"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.CreateGroup("mod"); err == nil {
+		t.Fatal("expected an error when creating a group that exists")
+	}
+	if contentChanged(&p.mu, p.path, &p.last) {
+		t.Fatal("expected writes of the permissions not to make them reload")
+	}
+	if err := p.SetGroupPermission("mod", "essential.command.kick", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetGroupPermission("mod", "essential..kick", true); err == nil {
+		t.Fatal("expected an error for an invalid node")
+	}
+	if err := p.AddPlayerGroup("Steve", "mod"); err != nil {
+		t.Fatal(err)
+	}
+	if !p.Has("Steve", "essential.command.kick") {
+		t.Fatal("expected Steve to be granted the permission of group mod")
+	}
+	if err := p.DeleteGroup(OperatorGroup); err == nil {
+		t.Fatal("expected an error when deleting the op group")
+	}
+	if err := p.DeleteGroup("mod"); err != nil {
+		t.Fatal(err)
+	}
+	if p.Has("Steve", "essential.command.kick") || len(p.Player("Steve").Groups) != 0 {
+		t.Fatal("expected Steve to be removed from the deleted group")
+	}
+
+	// The changes must have been written to the file.
+	if err := p.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.Group("mod"); ok {
+		t.Fatal("expected the deleted group not to be in the file")
+	}
+}
@@ -1,18 +1,128 @@
 package permission
 
 import (
+	"errors"
+	"fmt"
 	"path/filepath"
 
 	"github.com/Blackjack200/GracticeEssential/util"
 )
 
-var _banEntry = NewEntry(filepath.Join(util.WorkingPath, "banned-players.txt"), "CONSOLE")
-var _opEntry = NewEntry(filepath.Join(util.WorkingPath, "ops.txt"), "CONSOLE")
+var _banEntry *BanList
+var _opEntry *Entry
+var _permissionEntry *Permissions
+var _whitelistEntry *Whitelist
 
-func BanEntry() *Entry {
+// Setup opens the ban list, the operators, the permissions and the whitelist stored in files in the working
+// directory, except for those replaced using SetBanEntry, SetOpEntry or SetWhitelistEntry before. It is called
+// by server.SetupFunc, after which BanEntry, OpEntry, PermissionEntry and WhitelistEntry may be used.
+// An error is returned if one of the files cannot be read, such as after a hand edit left invalid JSON in it.
+func Setup() error {
+	if _banEntry == nil {
+		b, err := NewBanList(filepath.Join(util.WorkingPath, "banned-players.json"), filepath.Join(util.WorkingPath, "banned-players.txt"))
+		if err != nil {
+			return fmt.Errorf("error opening banned-players.json: %w", err)
+		}
+		_banEntry = b
+	}
+	if _opEntry == nil {
+		e, err := NewEntry(filepath.Join(util.WorkingPath, "ops.txt"))
+		if err != nil {
+			return fmt.Errorf("error opening ops.txt: %w", err)
+		}
+		_opEntry = e
+	}
+	if _permissionEntry == nil {
+		p, err := NewPermissions(filepath.Join(util.WorkingPath, "permissions.json"), _opEntry)
+		if err != nil {
+			return fmt.Errorf("error opening permissions.json: %w", err)
+		}
+		_permissionEntry = p
+	}
+	if _whitelistEntry == nil {
+		w, err := NewWhitelist(filepath.Join(util.WorkingPath, "whitelist.txt"), filepath.Join(util.WorkingPath, "whitelist.json"))
+		if err != nil {
+			return fmt.Errorf("error opening whitelist: %w", err)
+		}
+		_whitelistEntry = w
+	}
+	return nil
+}
+
+// Close closes the ban list, the operators, the permissions and the whitelist, which may no longer be used
+// afterwards.
+func Close() error {
+	var errs []error
+	if _banEntry != nil {
+		errs = append(errs, _banEntry.Close())
+	}
+	if _opEntry != nil {
+		errs = append(errs, _opEntry.Close())
+	}
+	if _permissionEntry != nil {
+		errs = append(errs, _permissionEntry.Close())
+	}
+	if _whitelistEntry != nil {
+		errs = append(errs, _whitelistEntry.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func BanEntry() *BanList {
 	return _banEntry
 }
 
 func OpEntry() *Entry {
 	return _opEntry
 }
+
+func WhitelistEntry() *Whitelist {
+	return _whitelistEntry
+}
+
+func PermissionEntry() *Permissions {
+	return _permissionEntry
+}
+
+// SetBanEntry replaces the BanList returned by BanEntry, for example with a BanList stored in a Storage that
+// is shared by several servers. It must be called before server.SetupFunc, so that the default ban list is
+// never opened. If a BanList was already set or opened, it is closed.
+func SetBanEntry(b *BanList) error {
+	prev := _banEntry
+	_banEntry = b
+	return closeReplaced(prev, b)
+}
+
+// SetOpEntry replaces the Entry returned by OpEntry, for example with an Entry stored in a Storage that is
+// shared by several servers. It must be called before server.SetupFunc, so that the default ops.txt is never
+// opened. If an Entry was already set or opened, it is closed.
+func SetOpEntry(e *Entry) error {
+	prev := _opEntry
+	_opEntry = e
+	if _permissionEntry != nil {
+		_permissionEntry.SetOperators(e)
+	}
+	return closeReplaced(prev, e)
+}
+
+// SetWhitelistEntry replaces the Whitelist returned by WhitelistEntry, for example with a Whitelist of which
+// the Entry is stored in a Storage that is shared by several servers. It must be called before
+// server.SetupFunc, so that the default whitelist is never opened. If a Whitelist was already set or opened,
+// it is closed.
+func SetWhitelistEntry(w *Whitelist) error {
+	prev := _whitelistEntry
+	_whitelistEntry = w
+	return closeReplaced(prev, w)
+}
+
+// closeReplaced closes the list prev replaced by the list passed, if it was set and is not the same list.
+func closeReplaced[T interface {
+	comparable
+	Close() error
+}](prev, replacement T) error {
+	var zero T
+	if prev == zero || prev == replacement {
+		return nil
+	}
+	return prev.Close()
+}
@@ -0,0 +1,45 @@
+package permission
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// closingStorage is a TextStorage that records whether it was closed.
+type closingStorage struct {
+	*TextStorage
+	closed bool
+}
+
+func (s *closingStorage) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestSetOpEntryClosesReplaced(t *testing.T) {
+	dir := t.TempDir()
+	a := &closingStorage{TextStorage: NewTextStorage(filepath.Join(dir, "a.txt"))}
+	b := &closingStorage{TextStorage: NewTextStorage(filepath.Join(dir, "b.txt"))}
+	ea, err := NewEntryWithStorage(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eb, err := NewEntryWithStorage(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer SetOpEntry(nil)
+
+	if err := SetOpEntry(ea); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetOpEntry(ea); err != nil || a.closed {
+		t.Fatalf("expected setting the same Entry again not to close it, got %v", err)
+	}
+	if err := SetOpEntry(eb); err != nil || !a.closed || b.closed {
+		t.Fatalf("expected only the replaced Entry to be closed, got %v", err)
+	}
+	if OpEntry() != eb {
+		t.Fatal("expected OpEntry to return the Entry set")
+	}
+}
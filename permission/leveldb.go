@@ -0,0 +1,48 @@
+package permission
+
+import (
+	"github.com/df-mc/goleveldb/leveldb"
+)
+
+// LevelDBStorage is a Storage that stores the items in an embedded LevelDB key-value database. The items are
+// loaded in the order of their keys. A LevelDB database may only be opened by one server at a time, so it
+// cannot be shared by several servers.
+type LevelDBStorage struct {
+	db *leveldb.DB
+}
+
+// NewLevelDBStorage opens the LevelDB database in the directory at the path passed, creating it if it does not
+// exist, and returns a LevelDBStorage that stores the items in it.
+func NewLevelDBStorage(path string) (*LevelDBStorage, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LevelDBStorage{db: db}, nil
+}
+
+// Load ...
+func (s *LevelDBStorage) Load() ([]Item, error) {
+	it := s.db.NewIterator(nil, nil)
+	defer it.Release()
+	var items []Item
+	for it.Next() {
+		items = append(items, Item{Key: string(it.Key()), Value: append([]byte(nil), it.Value()...)})
+	}
+	return items, it.Error()
+}
+
+// Put ...
+func (s *LevelDBStorage) Put(key string, value []byte) error {
+	return s.db.Put([]byte(key), value, nil)
+}
+
+// Delete ...
+func (s *LevelDBStorage) Delete(key string) error {
+	return s.db.Delete([]byte(key), nil)
+}
+
+// Close closes the database.
+func (s *LevelDBStorage) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,49 @@
+package permission
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLevelDBStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bans")
+	s, err := NewLevelDBStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items, err := s.Load(); err != nil || len(items) != 0 {
+		t.Fatalf("expected no items in a new database, got %v, %v", items, err)
+	}
+	for _, key := range []string{"name:steve", "ip:192.168.0.0/24", "name:alex"} {
+		if err := s.Put(key, []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Put("name:steve", []byte("replaced")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("name:alex"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = NewLevelDBStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	items, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Items are loaded in the order of their keys.
+	if want := []string{"ip:192.168.0.0/24", "name:steve"}; !slices.Equal(keys(items), want) {
+		t.Fatalf("expected keys %v, got %v", want, keys(items))
+	}
+	if string(items[1].Value) != "replaced" {
+		t.Fatalf("expected the value of name:steve to be replaced, got %q", items[1].Value)
+	}
+}
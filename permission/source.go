@@ -0,0 +1,121 @@
+package permission
+
+import (
+	"sync"
+
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+)
+
+// SourceKind is the kind of a command source, which determines its Capabilities.
+type SourceKind int
+
+const (
+	// SourceUnknown is the kind of command sources that are of none of the other kinds.
+	SourceUnknown SourceKind = iota
+	// SourceConsole is the kind of the server console.
+	SourceConsole
+	// SourcePlayer is the kind of players.
+	SourcePlayer
+	// SourceRCON is the kind of remote console connections. Like the console, it is granted every permission
+	// node by default, as only those knowing the RCON password can connect. SetCapabilities may be used to
+	// restrict it.
+	SourceRCON
+	// SourceCommandBlock is the kind of command blocks.
+	SourceCommandBlock
+	// SourceScheduledTask is the kind of tasks run by the server itself, such as scheduled commands.
+	SourceScheduledTask
+)
+
+// String ...
+func (k SourceKind) String() string {
+	switch k {
+	case SourceConsole:
+		return "console"
+	case SourcePlayer:
+		return "player"
+	case SourceRCON:
+		return "rcon"
+	case SourceCommandBlock:
+		return "command block"
+	case SourceScheduledTask:
+		return "scheduled task"
+	}
+	return "unknown"
+}
+
+// Source is implemented by command sources that know their SourceKind. Players are always of the kind
+// SourcePlayer and do not need to implement it.
+type Source interface {
+	cmd.Source
+	// SourceKind returns the kind of the source.
+	SourceKind() SourceKind
+}
+
+// KindOf returns the SourceKind of the command source passed.
+func KindOf(src cmd.Source) SourceKind {
+	switch src := src.(type) {
+	case *player.Player:
+		return SourcePlayer
+	case Source:
+		return src.SourceKind()
+	}
+	return SourceUnknown
+}
+
+// Capabilities is a set of capabilities that a kind of command source has.
+type Capabilities uint
+
+const (
+	// CapabilityAllNodes grants a source every permission node without consulting Permissions.
+	CapabilityAllNodes Capabilities = 1 << iota
+	// CapabilityNodes makes the permission nodes of a source resolved from Permissions using the name of the
+	// source, which must implement cmd.NamedTarget. Since a source is then granted the permissions of the
+	// player with the same name, it should only be given to kinds of which the names are those of players.
+	CapabilityNodes
+)
+
+// Has checks if all capabilities passed are in the Capabilities.
+func (c Capabilities) Has(o Capabilities) bool {
+	return c&o == o
+}
+
+var (
+	capabilitiesMu sync.RWMutex
+	capabilities   = map[SourceKind]Capabilities{
+		SourceConsole:       CapabilityAllNodes,
+		SourceRCON:          CapabilityAllNodes,
+		SourceScheduledTask: CapabilityAllNodes,
+		SourcePlayer:        CapabilityNodes,
+	}
+)
+
+// CapabilitiesOf returns the Capabilities of the SourceKind passed. By default, these are:
+//   - SourceConsole, SourceRCON and SourceScheduledTask: CapabilityAllNodes.
+//   - SourcePlayer: CapabilityNodes.
+//   - SourceCommandBlock and SourceUnknown: none.
+func CapabilitiesOf(k SourceKind) Capabilities {
+	capabilitiesMu.RLock()
+	defer capabilitiesMu.RUnlock()
+	return capabilities[k]
+}
+
+// SetCapabilities changes the Capabilities of the SourceKind passed.
+func SetCapabilities(k SourceKind, c Capabilities) {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+	capabilities[k] = c
+}
+
+// Allow checks if the command source passed has the permission node passed, according to the Capabilities
+// of its kind.
+func (p *Permissions) Allow(src cmd.Source, node string) bool {
+	c := CapabilitiesOf(KindOf(src))
+	if c.Has(CapabilityAllNodes) {
+		return true
+	}
+	if t, ok := src.(cmd.NamedTarget); ok && c.Has(CapabilityNodes) {
+		return p.Has(t.Name(), node)
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package permission
+
+import (
+	"testing"
+
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// namedSource is a command source with a name and optionally a SourceKind.
+type namedSource struct {
+	name string
+}
+
+func (s namedSource) Name() string                  { return s.name }
+func (s namedSource) Position() mgl64.Vec3          { return mgl64.Vec3{} }
+func (s namedSource) SendCommandOutput(*cmd.Output) {}
+
+// kindSource is a namedSource of the SourceKind held.
+type kindSource struct {
+	namedSource
+	kind SourceKind
+}
+
+func (s kindSource) SourceKind() SourceKind { return s.kind }
+
+// withPlayer calls f with a player with the name passed in the transaction of a new world. f is called on the
+// goroutine of the world, so it must use t.Error instead of t.Fatal.
+func withPlayer(t *testing.T, name string, f func(p *player.Player)) {
+	w := world.Config{Entities: entity.DefaultRegistry}.New()
+	t.Cleanup(func() {
+		_ = w.Close()
+	})
+	h := world.EntitySpawnOpts{}.New(player.Type, player.Config{Name: name})
+	<-w.Exec(func(tx *world.Tx) {
+		f(tx.AddEntity(h).(*player.Player))
+	})
+}
+
+func TestAllowConsoleName(t *testing.T) {
+	p := newPermissions(t, ``)
+
+	if !p.Allow(kindSource{namedSource{"CONSOLE"}, SourceConsole}, "essential.command.stop") {
+		t.Fatal("expected the console to be granted every permission")
+	}
+	if p.Allow(namedSource{"CONSOLE"}, "essential.command.stop") {
+		t.Fatal("expected a source of an unknown kind named CONSOLE to be denied")
+	}
+	withPlayer(t, "CONSOLE", func(pl *player.Player) {
+		if KindOf(pl) != SourcePlayer {
+			t.Errorf("expected a player to be of the kind player, got %v", KindOf(pl))
+		}
+		if p.Allow(pl, "essential.command.stop") {
+			t.Error("expected a player named CONSOLE to be denied")
+		}
+	})
+	if err := p.SetPlayerPermission("CONSOLE", "essential.command.stop", true); err != nil {
+		t.Fatal(err)
+	}
+	withPlayer(t, "CONSOLE", func(pl *player.Player) {
+		if !p.Allow(pl, "essential.command.stop") || p.Allow(pl, "essential.command.gc") {
+			t.Error("expected a player named CONSOLE to be granted only its own permissions")
+		}
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	defer SetCapabilities(SourceCommandBlock, CapabilitiesOf(SourceCommandBlock))
+	p := newPermissions(t, `{"players": {"steve": {"permissions": {"essential.command.kick": true}}}}`)
+	src := kindSource{namedSource{"Steve"}, SourceCommandBlock}
+
+	if p.Allow(src, "essential.command.kick") {
+		t.Fatal("expected command blocks to have no capabilities by default")
+	}
+	SetCapabilities(SourceCommandBlock, CapabilityNodes)
+	if !p.Allow(src, "essential.command.kick") || p.Allow(src, "essential.command.stop") {
+		t.Fatal("expected the permissions of a command block to be resolved by its name")
+	}
+	for _, k := range []SourceKind{SourceConsole, SourceRCON, SourceScheduledTask} {
+		if !CapabilitiesOf(k).Has(CapabilityAllNodes) {
+			t.Errorf("expected %v to be granted every permission by default", k)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package permission
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLStorage is a Storage that stores the items in a table of a SQLite database, which may be shared by
+// several servers. Next to the table, a table with a revision number that is increased on every change is
+// created, which SQLStorage polls to implement Watcher.
+// NewSQLiteStorage may be used to open a SQLite database file. Other databases must be opened using a SQLite
+// driver, such as modernc.org/sqlite, which must be imported by the program.
+type SQLStorage struct {
+	db *sql.DB
+	// table and revisionTable are the quoted names of the table holding the items and the table holding the
+	// revision number.
+	table, revisionTable string
+	interval             time.Duration
+	revision             atomic.Int64
+	// ownsDB specifies if the database was opened by the SQLStorage, in which case Close closes it.
+	ownsDB bool
+}
+
+// NewSQLStorage returns a SQLStorage that stores the items in the table with the name passed, creating the
+// table if it does not exist. Changes made by other servers are polled for at the interval passed. The table
+// name may only hold letters, digits and underscores, and may not start with a digit.
+func NewSQLStorage(db *sql.DB, table string, pollInterval time.Duration) (*SQLStorage, error) {
+	if !tableName.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+	if pollInterval <= 0 {
+		return nil, fmt.Errorf("poll interval must be positive")
+	}
+	s := &SQLStorage{db: db, table: `"` + table + `"`, revisionTable: `"` + table + `_revision"`, interval: pollInterval}
+	for _, query := range []string{
+		`CREATE TABLE IF NOT EXISTS ` + s.table + ` (item_key TEXT PRIMARY KEY, item_value BLOB)`,
+		`CREATE TABLE IF NOT EXISTS ` + s.revisionTable + ` (id INTEGER PRIMARY KEY CHECK (id = 0), revision INTEGER NOT NULL)`,
+		`INSERT OR IGNORE INTO ` + s.revisionTable + ` (id, revision) VALUES (0, 0)`,
+	} {
+		if _, err := db.Exec(query); err != nil {
+			return nil, err
+		}
+	}
+	rev, err := s.currentRevision(db)
+	if err != nil {
+		return nil, err
+	}
+	s.revision.Store(rev)
+	return s, nil
+}
+
+// Load ...
+func (s *SQLStorage) Load() ([]Item, error) {
+	rows, err := s.db.Query(`SELECT item_key, item_value FROM ` + s.table + ` ORDER BY rowid`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Item
+	for rows.Next() {
+		var item Item
+		if err := rows.Scan(&item.Key, &item.Value); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// Put ...
+func (s *SQLStorage) Put(key string, value []byte) error {
+	return s.change(`INSERT INTO `+s.table+` (item_key, item_value) VALUES (?, ?) ON CONFLICT(item_key) DO UPDATE SET item_value = excluded.item_value`, key, value)
+}
+
+// Delete ...
+func (s *SQLStorage) Delete(key string) error {
+	return s.change(`DELETE FROM `+s.table+` WHERE item_key = ?`, key)
+}
+
+// change executes the query passed and increases the revision number in a single transaction. The new
+// revision number is stored, so that Watch does not report the change as made by another server.
+func (s *SQLStorage) change(query string, args ...any) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(query, args...); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE ` + s.revisionTable + ` SET revision = revision + 1 WHERE id = 0`); err != nil {
+		return err
+	}
+	rev, err := s.currentRevision(tx)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.revision.Store(rev)
+	return nil
+}
+
+// currentRevision returns the revision number stored in the database.
+func (s *SQLStorage) currentRevision(q interface {
+	QueryRow(query string, args ...any) *sql.Row
+}) (int64, error) {
+	var rev int64
+	err := q.QueryRow(`SELECT revision FROM ` + s.revisionTable + ` WHERE id = 0`).Scan(&rev)
+	return rev, err
+}
+
+// Watch polls the revision number of the table and calls f when it was changed by another server. Errors
+// while polling are ignored, so that the storage is polled again after a temporary failure. stop may be called
+// more than once.
+func (s *SQLStorage) Watch(f func()) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		t := time.NewTicker(s.interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-t.C:
+				rev, err := s.currentRevision(s.db)
+				if err == nil && s.revision.Swap(rev) != rev {
+					f()
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// Close closes the database if it was opened by NewSQLiteStorage. Databases passed to NewSQLStorage are left
+// open, as they may be used by the program otherwise.
+func (s *SQLStorage) Close() error {
+	if s.ownsDB {
+		return s.db.Close()
+	}
+	return nil
+}
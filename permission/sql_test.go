@@ -0,0 +1,90 @@
+//go:build cgo
+
+package permission
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSQLStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "permissions.db")
+	s, err := NewSQLiteStorage(path, "bans", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if items, err := s.Load(); err != nil || len(items) != 0 {
+		t.Fatalf("expected no items in a new table, got %v, %v", items, err)
+	}
+	for _, key := range []string{"name:steve", "ip:192.168.0.0/24", "name:alex"} {
+		if err := s.Put(key, []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Put("name:steve", []byte("replaced")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("name:alex"); err != nil {
+		t.Fatal(err)
+	}
+	items, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"name:steve", "ip:192.168.0.0/24"}; !slices.Equal(keys(items), want) {
+		t.Fatalf("expected keys %v, got %v", want, keys(items))
+	}
+	if string(items[0].Value) != "replaced" {
+		t.Fatalf("expected the value of name:steve to be replaced, got %q", items[0].Value)
+	}
+}
+
+func TestSQLStorageWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "permissions.db")
+	a, err := NewSQLiteStorage(path, "bans", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	b, err := NewSQLiteStorage(path, "bans", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	changed := make(chan struct{}, 1)
+	stop := b.Watch(func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+	defer stop()
+	if err := b.Put("name:alex", nil); err != nil {
+		t.Fatal(err)
+	}
+	if rev, err := b.currentRevision(b.db); err != nil || b.revision.Load() != rev {
+		t.Fatalf("expected changes made through the storage itself not to be reported, got revision %v, %v", rev, err)
+	}
+	if err := a.Put("name:steve", nil); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a change made by another storage to be reported")
+	}
+	stop()
+	stop()
+}
+
+func TestSQLStorageTableName(t *testing.T) {
+	for _, table := range []string{"", "1bans", "bans; DROP TABLE ops", `bans"`, "ban-list"} {
+		if _, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "permissions.db"), table, time.Second); err == nil {
+			t.Errorf("expected table name %q to be rejected", table)
+		}
+	}
+}
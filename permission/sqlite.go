@@ -0,0 +1,29 @@
+package permission
+
+import (
+	"database/sql"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// NewSQLiteStorage opens the SQLite database file at the path passed, creating it if it does not exist, and
+// returns a SQLStorage that stores the items in the table with the name passed. The database is closed when
+// the SQLStorage is closed. Several servers may share the file, as long as it is not on a network file system.
+// The driver used requires cgo: if the program is built without it, an error is returned.
+func NewSQLiteStorage(path, table string, pollInterval time.Duration) (*SQLStorage, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?_busy_timeout=5000&_txlock=immediate")
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer at a time, so a single connection prevents the servers from
+	// locking the database on themselves.
+	db.SetMaxOpenConns(1)
+	s, err := NewSQLStorage(db, table, pollInterval)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	s.ownsDB = true
+	return s, nil
+}
@@ -0,0 +1,308 @@
+package permission
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Blackjack200/GracticeEssential/util"
+)
+
+// Item is a value stored in a Storage under a key.
+type Item struct {
+	Key   string
+	Value []byte
+}
+
+// Storage is a storage backend of an Entry or a BanList. It stores values by key, in the order they were
+// first stored, if the backend keeps an order. Storages that are shared by several servers should implement
+// Watcher, so that changes made by one server are seen by the others.
+type Storage interface {
+	// Load returns all items stored.
+	Load() ([]Item, error)
+	// Put stores the value passed under the key passed, replacing the value stored under it, if any.
+	Put(key string, value []byte) error
+	// Delete deletes the value stored under the key passed, if any.
+	Delete(key string) error
+}
+
+// Watcher is implemented by Storages that detect changes made to them by other servers.
+type Watcher interface {
+	// Watch calls f every time the items stored are changed by another server, until the function returned is
+	// called.
+	Watch(f func()) (stop func())
+}
+
+// WatchInterval is the interval at which the files of a TextStorage or a JSONStorage are polled for changes on
+// systems that do not support notifications of file changes.
+var WatchInterval = 2 * time.Second
+
+// TextStorage is a Storage that stores the keys of the items one per line in a text file. The values of the
+// items are not stored. The file is written using util.WriteFile, so that it is never left partially written
+// and backups of its previous versions are kept.
+// TextStorage implements Watcher, so that changes made to the file by hand or by other programs are seen.
+type TextStorage struct {
+	mu   sync.Mutex
+	path string
+	last []byte
+}
+
+// NewTextStorage returns a TextStorage that stores the keys in the file at the path passed.
+func NewTextStorage(path string) *TextStorage {
+	return &TextStorage{path: path}
+}
+
+// Load returns the items of the keys in the file, creating the file if it does not exist.
+func (s *TextStorage) Load() ([]Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loadNoLock()
+}
+
+func (s *TextStorage) loadNoLock() ([]Item, error) {
+	if !util.FileExist(s.path) {
+		s.last = nil
+		return nil, util.WriteFile(s.path, nil)
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	s.last = data
+	var items []Item
+	for _, line := range strings.Split(string(data), "\n") {
+		if len(strings.TrimSpace(line)) != 0 {
+			items = append(items, Item{Key: line})
+		}
+	}
+	return items, nil
+}
+
+func (s *TextStorage) writeNoLock(items []Item) error {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = item.Key
+	}
+	data := []byte(strings.Join(keys, "\n"))
+	if err := util.WriteFile(s.path, data); err != nil {
+		return err
+	}
+	s.last = data
+	return nil
+}
+
+// Put adds the key passed to the file, if it is not yet in it. The value is ignored.
+func (s *TextStorage) Put(key string, _ []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items, err := s.loadNoLock()
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.Key == key {
+			return nil
+		}
+	}
+	return s.writeNoLock(append(items, Item{Key: key}))
+}
+
+// Delete removes the key passed from the file.
+func (s *TextStorage) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items, err := s.loadNoLock()
+	if err != nil {
+		return err
+	}
+	return s.writeNoLock(deleteItem(items, key))
+}
+
+// Watch calls f every time the file is changed other than through the TextStorage.
+func (s *TextStorage) Watch(f func()) (stop func()) {
+	return watchFile(&s.mu, s.path, &s.last, f)
+}
+
+// JSONStorage is a Storage that stores the values of the items as a JSON array in a file. The values must be
+// JSON values, from which the keys of the items are derived. Like that of a TextStorage, the file is written
+// using util.WriteFile.
+// JSONStorage implements Watcher, so that changes made to the file by hand or by other programs are seen.
+type JSONStorage struct {
+	mu   sync.Mutex
+	path string
+	key  func(value []byte) (string, error)
+	last []byte
+}
+
+// NewJSONStorage returns a JSONStorage that stores the values in the file at the path passed and derives the
+// key of every value using the key function passed.
+func NewJSONStorage(path string, key func(value []byte) (string, error)) *JSONStorage {
+	return &JSONStorage{path: path, key: key}
+}
+
+// Load returns the items of the values in the file, creating the file if it does not exist.
+func (s *JSONStorage) Load() ([]Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loadNoLock()
+}
+
+func (s *JSONStorage) loadNoLock() ([]Item, error) {
+	if !util.FileExist(s.path) {
+		s.last = []byte("[]")
+		return nil, util.WriteFile(s.path, s.last)
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	s.last = data
+	var values []json.RawMessage
+	if len(strings.TrimSpace(string(data))) != 0 {
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+	}
+	items := make([]Item, 0, len(values))
+	for _, v := range values {
+		key, err := s.key(v)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, Item{Key: key, Value: v})
+	}
+	return items, nil
+}
+
+func (s *JSONStorage) writeNoLock(items []Item) error {
+	values := make([]json.RawMessage, len(items))
+	for i, item := range items {
+		values[i] = item.Value
+	}
+	data, err := json.MarshalIndent(values, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := util.WriteFile(s.path, data); err != nil {
+		return err
+	}
+	s.last = data
+	return nil
+}
+
+// Put stores the value passed in the file, replacing the value with the same key, if any.
+func (s *JSONStorage) Put(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items, err := s.loadNoLock()
+	if err != nil {
+		return err
+	}
+	for i, item := range items {
+		if item.Key == key {
+			items[i].Value = value
+			return s.writeNoLock(items)
+		}
+	}
+	return s.writeNoLock(append(items, Item{Key: key, Value: value}))
+}
+
+// Delete removes the value with the key passed from the file.
+func (s *JSONStorage) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items, err := s.loadNoLock()
+	if err != nil {
+		return err
+	}
+	return s.writeNoLock(deleteItem(items, key))
+}
+
+// Watch calls f every time the file is changed other than through the JSONStorage.
+func (s *JSONStorage) Watch(f func()) (stop func()) {
+	return watchFile(&s.mu, s.path, &s.last, f)
+}
+
+// watchFile watches the file at the path passed and calls f every time its content differs from the content
+// last read or written, which is guarded by the mutex passed. Writes made through util.WriteFile are reported
+// by util.WatchFile too, so comparing the content keeps a list from reloading after its own writes.
+func watchFile(mu *sync.Mutex, path string, last *[]byte, f func()) (stop func()) {
+	return util.WatchFile(path, WatchInterval, func() {
+		if contentChanged(mu, path, last) {
+			f()
+		}
+	})
+}
+
+// contentChanged checks if the content of the file at the path passed differs from the content last read or
+// written, which is guarded by the mutex passed.
+func contentChanged(mu *sync.Mutex, path string, last *[]byte) bool {
+	data, _ := os.ReadFile(path)
+	mu.Lock()
+	defer mu.Unlock()
+	return !bytes.Equal(data, *last)
+}
+
+// deleteItem returns the items passed without the item with the key passed.
+func deleteItem(items []Item, key string) []Item {
+	a := items[:0:0]
+	for _, item := range items {
+		if item.Key != key {
+			a = append(a, item)
+		}
+	}
+	return a
+}
+
+// notifier holds the hooks called when an Entry or a BanList is changed by another server or program.
+type notifier struct {
+	mu    sync.Mutex
+	hooks []func()
+}
+
+// OnChange registers a function that is called after the list was reloaded because another server or program
+// changed its Storage, such as an edit of its file made by hand. It is not called for changes made through
+// this list. The function is called from a separate goroutine and not within a transaction.
+func (n *notifier) OnChange(f func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.hooks = append(n.hooks, f)
+}
+
+func (n *notifier) notify() {
+	n.mu.Lock()
+	hooks := append([]func(){}, n.hooks...)
+	n.mu.Unlock()
+	for _, f := range hooks {
+		f()
+	}
+}
+
+// watch makes reload and the hooks of the notifier passed be called when the Storage passed is changed by
+// another server or program, if it implements Watcher. If reload fails, for example because a file was edited
+// by hand and holds invalid JSON, the list is left unchanged and the hooks are not called. The function
+// returned stops watching the Storage.
+func watch(s Storage, reload func() error, n *notifier) (stop func()) {
+	w, ok := s.(Watcher)
+	if !ok {
+		return func() {}
+	}
+	return w.Watch(func() {
+		if reload() == nil {
+			n.notify()
+		}
+	})
+}
+
+// closeStorage calls stop and closes the Storage passed, if it implements io.Closer.
+func closeStorage(s Storage, stop func()) error {
+	stop()
+	if c, ok := s.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package permission
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// keys returns the keys of the items passed.
+func keys(items []Item) []string {
+	k := make([]string, len(items))
+	for i, item := range items {
+		k[i] = item.Key
+	}
+	return k
+}
+
+func TestTextStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ops.txt")
+	s := NewTextStorage(path)
+	if items, err := s.Load(); err != nil || len(items) != 0 {
+		t.Fatalf("expected no items in a new file, got %v, %v", items, err)
+	}
+	for _, key := range []string{"Steve", "Alex", "Steve"} {
+		if err := s.Put(key, []byte("ignored")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Delete("Alex"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("Notch", nil); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "Steve\nNotch" {
+		t.Fatalf("unexpected file content %q", data)
+	}
+
+	if err := os.WriteFile(path, []byte("Steve\n\n  \nHerobrine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	items, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Steve", "Herobrine"}; !slices.Equal(keys(items), want) {
+		t.Fatalf("expected keys %v, got %v", want, keys(items))
+	}
+}
+
+func TestJSONStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "banned-players.json")
+	s := NewJSONStorage(path, banKey)
+	put := func(r BanRecord) {
+		data, _ := json.Marshal(r)
+		if err := s.Put(r.key(), data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put(BanRecord{Name: "Steve", Reason: "griefing"})
+	put(BanRecord{IP: "192.168.0.0/24"})
+	put(BanRecord{Name: "steve", Reason: "spam"})
+
+	items, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"name:steve", "ip:192.168.0.0/24"}; !slices.Equal(keys(items), want) {
+		t.Fatalf("expected keys %v, got %v", want, keys(items))
+	}
+	var r BanRecord
+	if err := json.Unmarshal(items[0].Value, &r); err != nil || r.Reason != "spam" {
+		t.Fatalf("expected the ban of Steve to be replaced, got %+v, %v", r, err)
+	}
+
+	if err := s.Delete("ip:192.168.0.0/24"); err != nil {
+		t.Fatal(err)
+	}
+	if items, _ := s.Load(); len(items) != 1 {
+		t.Fatalf("expected one item after deleting, got %v", keys(items))
+	}
+	if err := os.WriteFile(path, []byte(`[{"name": `), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Load(); err == nil {
+		t.Fatal("expected an error for a file holding invalid JSON")
+	}
+}
+
+func TestContentChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ops.txt")
+	s := NewTextStorage(path)
+	if _, err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("Steve", nil); err != nil {
+		t.Fatal(err)
+	}
+	if contentChanged(&s.mu, path, &s.last) {
+		t.Fatal("expected writes through the storage not to be reported")
+	}
+	if err := os.WriteFile(path, []byte("Steve\nAlex"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !contentChanged(&s.mu, path, &s.last) {
+		t.Fatal("expected an external change to be reported")
+	}
+}
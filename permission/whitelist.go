@@ -0,0 +1,176 @@
+package permission
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Blackjack200/GracticeEssential/util"
+	"github.com/df-mc/dragonfly/server"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+)
+
+// DefaultWhitelistMessage is the message players that are not whitelisted are disconnected with if no other
+// message is configured.
+const DefaultWhitelistMessage = "You are not whitelisted on this server"
+
+// whitelistConfig is the configuration of a Whitelist as stored in its configuration file.
+type whitelistConfig struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message"`
+}
+
+// Whitelist is an Entry of the players allowed to join the server while the whitelist is enabled. The names,
+// XUIDs, IP addresses and CIDR ranges of the players are stored one per line in the file of the Entry, while
+// whether the whitelist is enabled and the message players are disconnected with are stored as JSON in a
+// separate configuration file.
+type Whitelist struct {
+	*Entry
+	mu         sync.Mutex
+	configPath string
+	cfg        whitelistConfig
+	stop       func()
+	// last is the content of the configuration file last read or written.
+	last []byte
+}
+
+// NewWhitelist returns a Whitelist stored at the path passed, with its configuration stored at configPath.
+// The whitelist is disabled if no configuration file exists yet. An error is returned if either file cannot be
+// read.
+func NewWhitelist(path, configPath string) (*Whitelist, error) {
+	e, err := NewEntry(path)
+	if err != nil {
+		return nil, err
+	}
+	w, err := NewWhitelistWithEntry(e, configPath)
+	if err != nil {
+		_ = e.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// NewWhitelistWithEntry returns a Whitelist of the players in the Entry passed, with its configuration stored
+// at configPath. An error is returned if the configuration file cannot be read or holds invalid JSON.
+func NewWhitelistWithEntry(e *Entry, configPath string) (*Whitelist, error) {
+	w := &Whitelist{Entry: e, configPath: configPath}
+	if err := w.reloadConfig(); err != nil {
+		return nil, fmt.Errorf("read %v: %w", configPath, err)
+	}
+	w.stop = watchFile(&w.mu, configPath, &w.last, func() {
+		_ = w.reloadConfig()
+	})
+	return w, nil
+}
+
+// Close stops watching the configuration file of the Whitelist for changes and closes its Entry.
+func (w *Whitelist) Close() error {
+	w.stop()
+	return w.Entry.Close()
+}
+
+// writeConfigNoLock writes the configuration passed to the configuration file of the Whitelist, after which it
+// becomes the configuration of the Whitelist. If it cannot be written, the configuration is left unchanged.
+func (w *Whitelist) writeConfigNoLock(cfg whitelistConfig) error {
+	b, err := json.MarshalIndent(cfg, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := util.WriteFile(w.configPath, b); err != nil {
+		return fmt.Errorf("write whitelist config: %w", err)
+	}
+	w.cfg, w.last = cfg, b
+	return nil
+}
+
+// reloadConfig reads the configuration of the Whitelist from its file again. If the file cannot be read or
+// holds invalid JSON, the configuration is left unchanged.
+func (w *Whitelist) reloadConfig() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	cfg := whitelistConfig{Message: DefaultWhitelistMessage}
+	if !util.FileExist(w.configPath) {
+		return w.writeConfigNoLock(cfg)
+	}
+	data, err := os.ReadFile(w.configPath)
+	if err != nil {
+		return err
+	}
+	if len(strings.TrimSpace(string(data))) != 0 {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return err
+		}
+	}
+	w.cfg, w.last = cfg, data
+	return nil
+}
+
+// Reload reads the entries and the configuration of the Whitelist from their files again. If either cannot
+// be read, it is left unchanged and an error is returned.
+func (w *Whitelist) Reload() error {
+	if err := w.Entry.Reload(); err != nil {
+		return err
+	}
+	return w.reloadConfig()
+}
+
+// Enabled checks if the whitelist is enabled.
+func (w *Whitelist) Enabled() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.cfg.Enabled
+}
+
+// SetEnabled enables or disables the whitelist.
+func (w *Whitelist) SetEnabled(enabled bool) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	cfg := w.cfg
+	cfg.Enabled = enabled
+	return w.writeConfigNoLock(cfg)
+}
+
+// Message returns the message players that are not whitelisted are disconnected with.
+func (w *Whitelist) Message() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.cfg.Message
+}
+
+// SetMessage changes the message players that are not whitelisted are disconnected with. If the message is
+// empty, DefaultWhitelistMessage is used.
+func (w *Whitelist) SetMessage(msg string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if msg == "" {
+		msg = DefaultWhitelistMessage
+	}
+	cfg := w.cfg
+	cfg.Message = msg
+	return w.writeConfigNoLock(cfg)
+}
+
+// ServerAllower returns a server.Allower that, while the whitelist is enabled, disallows players that are
+// neither on the whitelist nor in the ops Entry passed from joining. ops may be nil.
+func (w *Whitelist) ServerAllower(ops *Entry) server.Allower {
+	return whitelistServerAllower{w: w, ops: ops}
+}
+
+type whitelistServerAllower struct {
+	w   *Whitelist
+	ops *Entry
+}
+
+func (a whitelistServerAllower) Allow(addr net.Addr, d login.IdentityData, _ login.ClientData) (string, bool) {
+	if !a.w.Enabled() {
+		return "", true
+	}
+	ip := IPOf(addr)
+	if a.w.Match(d.DisplayName, d.XUID, ip) || (a.ops != nil && a.ops.Match(d.DisplayName, d.XUID, ip)) {
+		return "", true
+	}
+	return a.w.Message(), false
+}
@@ -0,0 +1,59 @@
+package permission
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWhitelistConfig(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "whitelist.json")
+	w, err := NewWhitelist(filepath.Join(dir, "whitelist.txt"), configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if w.Enabled() || w.Message() != DefaultWhitelistMessage {
+		t.Fatal("expected a new whitelist to be disabled with the default message")
+	}
+	if err := w.SetEnabled(true); err != nil {
+		t.Fatal(err)
+	}
+	if contentChanged(&w.mu, configPath, &w.last) {
+		t.Fatal("expected writes of the whitelist not to make it reload its configuration")
+	}
+
+	if err := os.WriteFile(configPath, []byte(`{"enabled": false, "message": "Closed"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !contentChanged(&w.mu, configPath, &w.last) {
+		t.Fatal("expected an edit of the configuration to make the whitelist reload it")
+	}
+	if err := w.reloadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if w.Enabled() || w.Message() != "Closed" {
+		t.Fatal("expected the edited configuration to be loaded")
+	}
+}
+
+func TestWhitelistClose(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewWhitelist(filepath.Join(dir, "whitelist.txt"), filepath.Join(dir, "whitelist.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stopped := false
+	stop := w.stop
+	w.stop = func() {
+		stopped = true
+		stop()
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !stopped {
+		t.Fatal("expected closing the whitelist to stop watching its configuration")
+	}
+}
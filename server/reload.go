@@ -0,0 +1,86 @@
+package server
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Blackjack200/GracticeEssential/permission"
+	"github.com/sandertv/gophertunnel/minecraft"
+)
+
+var _configMu sync.Mutex
+
+// Reload reloads the ban list, the operators, the whitelist and the permissions from their files, as well as
+// the settings in config.toml that may be changed while the server is running, which is the name of the
+// server. The other settings in config.toml that changed since the server started are returned, as they only
+// apply after a restart.
+// Players online that are banned after reloading the ban list are disconnected. If a list cannot be read, it
+// is left unchanged, the other lists are still reloaded and the errors are returned.
+func Reload() (restart []string, err error) {
+	err = errors.Join(
+		permission.BanEntry().Reload(),
+		permission.OpEntry().Reload(),
+		permission.WhitelistEntry().Reload(),
+		permission.PermissionEntry().Reload(),
+	)
+	// Reload may be called from within a transaction, such as by a command, while disconnecting the players
+	// requires the transactions of their worlds.
+	go disconnectBanned()
+	restart, cfgErr := reloadConfig()
+	return restart, errors.Join(err, cfgErr)
+}
+
+// reloadConfig reads config.toml again and applies the settings that may be changed while the server is
+// running. The other settings that changed since the server started are returned.
+func reloadConfig() ([]string, error) {
+	uc, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+	_configMu.Lock()
+	defer _configMu.Unlock()
+	_config.Server.Name = uc.Server.Name
+	_status.setName(uc.Server.Name)
+	return changedSettings(reflect.ValueOf(_config), reflect.ValueOf(uc), ""), nil
+}
+
+// changedSettings returns the names of the fields that differ between the structs passed, such as
+// Network.Address.
+func changedSettings(a, b reflect.Value, prefix string) []string {
+	var changed []string
+	for i := 0; i < a.NumField(); i++ {
+		name := prefix + a.Type().Field(i).Name
+		if a.Field(i).Kind() == reflect.Struct {
+			changed = append(changed, changedSettings(a.Field(i), b.Field(i), name+".")...)
+		} else if !reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// statusProvider is a minecraft.ServerStatusProvider of which the server name may be changed while the server
+// is running.
+type statusProvider struct {
+	name atomic.Pointer[string]
+}
+
+// setName changes the server name shown in the server list. If the name is empty, the default name of
+// dragonfly is shown.
+func (s *statusProvider) setName(name string) {
+	if name == "" {
+		name = "Dragonfly Server"
+	}
+	s.name.Store(&name)
+}
+
+// ServerStatus ...
+func (s *statusProvider) ServerStatus(playerCount, maxPlayers int) minecraft.ServerStatus {
+	return minecraft.ServerStatus{
+		ServerName:  *s.name.Load(),
+		PlayerCount: playerCount,
+		MaxPlayers:  maxPlayers,
+	}
+}
@@ -2,18 +2,25 @@ package server
 
 import (
 	"fmt"
+	"github.com/Blackjack200/GracticeEssential/mhandler"
 	"github.com/Blackjack200/GracticeEssential/permission"
 	"github.com/Blackjack200/GracticeEssential/util"
 	"github.com/df-mc/dragonfly/server"
 	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
 	"github.com/pelletier/go-toml"
 	"log/slog"
 	"os"
+	"sort"
 	"time"
 )
 
 var _global *server.Server
 var _startDate time.Time
+var _log *slog.Logger
+var _config server.UserConfig
+var _status = &statusProvider{}
+var _stopConfigWatch = func() {}
 
 func Global() *server.Server {
 	return _global
@@ -23,19 +30,50 @@ func SetupFunc(l *slog.Logger, cfgFunc func(*server.Config)) error {
 	util.PanicFunc(func(v interface{}) {
 		panic(v)
 	})
-	if cfg, err := readConfig(); err != nil {
+	if err := permission.Setup(); err != nil {
+		return err
+	}
+	if uc, err := readConfig(); err != nil {
 		return err
 	} else {
-		cfg := util.SelectNotNil[server.Config](cfg.Config(l))
-		cfg.Allower = permission.BanEntry().ServerAllower("You are banned", false)
+		_log, _config = l, uc
+		cfg := util.SelectNotNil[server.Config](uc.Config(l))
+		_status.setName(uc.Server.Name)
+		cfg.StatusProvider = _status
+		cfg.Allower = util.LinkServerAllower(
+			permission.BanEntry().ServerAllower(),
+			permission.WhitelistEntry().ServerAllower(permission.OpEntry()),
+		)
 		if cfgFunc != nil {
 			cfgFunc(&cfg)
 		}
 		_global = cfg.New()
+		permission.BanEntry().OnChange(disconnectBanned)
+		_stopConfigWatch = util.WatchFile("config.toml", permission.WatchInterval, func() {
+			if restart, err := reloadConfig(); err != nil {
+				l.Error("failed reloading config.toml", "err", err)
+			} else if len(restart) != 0 {
+				l.Warn("changed settings in config.toml only apply after a restart", "settings", restart)
+			}
+		})
 	}
 	return nil
 }
 
+// disconnectBanned disconnects the players online that are banned, such as after another server sharing the
+// ban list banned them.
+func disconnectBanned() {
+	if Global() == nil {
+		return
+	}
+	now := time.Now()
+	for p := range Global().Players(nil) {
+		if r, ok := permission.BanEntry().Match(p.Name(), p.XUID(), permission.IPOf(p.Addr())); ok {
+			p.Disconnect(r.Message(now))
+		}
+	}
+}
+
 func Start() {
 	Global().Listen()
 	_startDate = time.Now()
@@ -43,6 +81,13 @@ func Start() {
 
 var Stop = func() {}
 
+// Close stops watching config.toml for changes and closes the ban list, the operators, the whitelist and the
+// permissions. It is called after the server was closed.
+func Close() error {
+	_stopConfigWatch()
+	return permission.Close()
+}
+
 func readConfig() (server.UserConfig, error) {
 	c := server.DefaultConfig()
 	if !util.FileExist("config.toml") {
@@ -65,15 +110,32 @@ func readConfig() (server.UserConfig, error) {
 	return c, nil
 }
 
+// Loop accepts players joining the server until it is closed. Every player accepted has a
+// mhandler.MultipleHandler attached, which calls the handlers registered to mhandler.Global(), before h is
+// called with the player.
 func Loop(h func(p *player.Player), end func()) {
 	for p := range Global().Accept() {
-		h(p)
+		mhandler.Attach(p)
+		if h != nil {
+			h(p)
+		}
 		if end != nil {
 			end()
 		}
 	}
 }
 
+// PlayerNames returns the sorted names of the players online. If PlayerNames is called from within a
+// transaction, the transaction must be passed.
+func PlayerNames(tx *world.Tx) []string {
+	var names []string
+	for p := range Global().Players(tx) {
+		names = append(names, p.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
 func Uptime() time.Duration {
 	return time.Now().Sub(_startDate)
 }
@@ -7,8 +7,14 @@ import (
 var _dragonflyVersion = func() string {
 	if info, ok := debug.ReadBuildInfo(); ok {
 		for _, dep := range info.Deps {
-			// TODO replace support
 			if dep.Path == "github.com/df-mc/dragonfly" {
+				// Replaced modules are shown in the same form as in the header of the generated handlers.
+				if r := dep.Replace; r != nil {
+					if r.Version == "" {
+						return dep.Path + "@" + dep.Version + " => " + r.Path
+					}
+					return dep.Path + "@" + dep.Version + " => " + r.Path + "@" + r.Version
+				}
 				return dep.Version
 			}
 		}
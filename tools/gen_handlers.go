@@ -1,61 +1,140 @@
 package main
 
 import (
-	"github.com/dave/jennifer/jen"
-	"github.com/df-mc/dragonfly/server/player"
+	"bytes"
+	"flag"
+	"fmt"
 	"go/ast"
-	"go/build"
-	"go/doc"
-	"go/parser"
-	"go/token"
-	"io"
+	"go/types"
+	"log"
 	"os"
-	"path/filepath"
-	"reflect"
-	"runtime/debug"
 	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/imports"
 )
 
+// target is a handler interface of dragonfly that a multiplexing handler is generated for.
+type target struct {
+	// pkg is the import path of the package holding the Handler interface.
+	pkg string
+	// name is the name of the multiplexing handler generated.
+	name string
+	// prefix is prepended to the names of the single method interfaces generated.
+	prefix string
+	out    string
+}
+
+const eventPkg = "github.com/df-mc/dragonfly/server/event"
+
+// The tool is run using go generate from the directives in mhandler/handler.go, once for every handler
+// interface. The -check flag may also be enabled by setting GEN_HANDLERS_CHECK, so that the directives may
+// be used to verify the generated files too.
 func main() {
-	f := jen.NewFile("mhandler")
+	var t target
+	flag.StringVar(&t.pkg, "pkg", "", "import path of the package holding the Handler interface")
+	flag.StringVar(&t.name, "name", "", "name of the multiplexing handler generated")
+	flag.StringVar(&t.prefix, "prefix", "", "prefix of the names of the single method interfaces generated")
+	flag.StringVar(&t.out, "out", "", "path of the file generated")
+	check := flag.Bool("check", os.Getenv("GEN_HANDLERS_CHECK") != "", "verify that the generated file is up to date instead of writing it")
+	flag.Parse()
+	if t.pkg == "" || t.name == "" || t.out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
 
-	if i, ok := debug.ReadBuildInfo(); ok {
-		for _, m := range i.Deps {
-			if m.Path == "github.com/df-mc/dragonfly" {
-				code := gen(getDoc(m), f)
-				w, err := os.OpenFile("./mhandler/generated.go", os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0666)
-				if err != nil {
-					panic(err)
-				}
-				if _, err := io.WriteString(w, code); err != nil {
-					panic(err)
-				}
-				w.Close()
-				break
-			}
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax | packages.NeedModule,
+	}, t.pkg)
+	if err != nil {
+		log.Fatalf("error loading package %v: %v", t.pkg, err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		os.Exit(1)
+	}
+	code, err := gen(pkgs[0], t)
+	if err != nil {
+		log.Fatalf("error generating %v: %v", t.out, err)
+	}
+	if *check {
+		existing, err := os.ReadFile(t.out)
+		if err != nil {
+			log.Fatalf("error reading %v: %v", t.out, err)
+		}
+		if !bytes.Equal(existing, code) {
+			log.Fatalf("%v is out of date, run 'make code' to regenerate it", t.out)
 		}
+		return
+	}
+	if err := os.WriteFile(t.out, code, 0666); err != nil {
+		log.Fatalf("error writing %v: %v", t.out, err)
 	}
 }
 
-func gen(d *doc.Package, f *jen.File) string {
-	reflectionIface := reflect.TypeOf(player.NopHandler{})
+// moduleVersion returns the version of the module passed, taking replace directives into account.
+func moduleVersion(m *packages.Module) string {
+	if m == nil {
+		return "(unknown)"
+	}
+	if r := m.Replace; r != nil {
+		if r.Version == "" {
+			return m.Path + "@" + m.Version + " => " + r.Path
+		}
+		return m.Path + "@" + m.Version + " => " + r.Path + "@" + r.Version
+	}
+	return m.Path + "@" + m.Version
+}
+
+func gen(pkg *packages.Package, tg target) ([]byte, error) {
+	obj, ok := pkg.Types.Scope().Lookup("Handler").(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("no Handler type in package %v", pkg.PkgPath)
+	}
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil, fmt.Errorf("%v.Handler is not an interface", pkg.PkgPath)
+	}
+	ifaceType, err := findInterface(pkg, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	f := jen.NewFile("mhandler")
+	f.HeaderComment(fmt.Sprintf("Code generated by tools/gen_handlers.go from %v. DO NOT EDIT.", moduleVersion(pkg.Module)))
+	genFromInterface(ifaceType, iface, tg, f)
+	return imports.Process(tg.out, []byte(f.GoString()), nil)
+}
 
-	for _, t := range d.Types {
-		if t.Name == "Handler" {
-			ifaceType := t.Decl.Specs[0].(*ast.TypeSpec).Type.(*ast.InterfaceType)
-			genFromInterface(ifaceType, reflectionIface, f)
-			break
+// findInterface returns the syntax tree of the interface declared by obj. It is used to retain the order and
+// doc comments of the methods, which are lost in the type information.
+func findInterface(pkg *packages.Package, obj *types.TypeName) (*ast.InterfaceType, error) {
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				if spec, ok := spec.(*ast.TypeSpec); ok && spec.Name.Pos() == obj.Pos() {
+					if ifaceType, ok := spec.Type.(*ast.InterfaceType); ok {
+						return ifaceType, nil
+					}
+				}
+			}
 		}
 	}
-	return f.GoString()
+	return nil, fmt.Errorf("no declaration of %v found", obj.Name())
 }
 
-func genFromInterface(ifaceType *ast.InterfaceType, reflectionIface reflect.Type, f *jen.File) {
+func genFromInterface(ifaceType *ast.InterfaceType, iface *types.Interface, tg target, f *jen.File) {
+	tableName := strings.ToLower(tg.name[:1]) + tg.name[1:] + "Table"
+
 	for _, method := range ifaceType.Methods.List {
 		for _, methodName := range method.Names {
 			originalMethodName := methodName.Name
-			typedIn, _ := getFuncIn(method, reflectionIface, originalMethodName)
-			newInterfaceName := strings.TrimPrefix(originalMethodName, "Handle") + "Handler"
+			typedIn, _ := getFuncIn(method, iface, originalMethodName)
+			newInterfaceName := tg.prefix + strings.TrimPrefix(originalMethodName, "Handle") + "Handler"
 			var stmt []jen.Code
 			for _, comm := range method.Doc.List {
 				stmt = append(stmt, jen.Comment(comm.Text))
@@ -69,106 +148,143 @@ func genFromInterface(ifaceType *ast.InterfaceType, reflectionIface reflect.Type
 	for _, method := range ifaceType.Methods.List {
 		for _, methodName := range method.Names {
 			originalMethodName := methodName.Name
-			typedIn, paramIn := getFuncIn(method, reflectionIface, originalMethodName)
-			newInterfaceName := strings.TrimPrefix(originalMethodName, "Handle") + "Handler"
+			typedIn, paramIn := getFuncIn(method, iface, originalMethodName)
+			newInterfaceName := tg.prefix + strings.TrimPrefix(originalMethodName, "Handle") + "Handler"
 			newFieldName := "_" + newInterfaceName
 
+			var body []jen.Code
+			if ctx, ok := getCtxParam(method, iface, originalMethodName); ok {
+				body = append(body, jen.If(jen.Id("ent").Dot("skip").Call(jen.Id(ctx))).Block(jen.Continue()))
+			}
+			body = append(body, jen.Id("ent").Dot("hdr").Dot(originalMethodName).Call(paramIn...))
+
 			f.Func().
-				Params(jen.Id("h").Id("*MultipleHandler")).Id(originalMethodName).
+				Params(jen.Id("h").Id("*" + tg.name)).Id(originalMethodName).
 				Params(typedIn...).
 				Block(
 					jen.For(
-						jen.List(jen.Id("_"), jen.Id("hdr")).Op(":=").Range().Id("h." + newFieldName),
-					).Block(
-						jen.If(
-							jen.List(jen.Id("hdr"), jen.Id("ok")).Op(":=").Op("hdr").Assert(jen.Id(newInterfaceName)),
-							jen.Id("ok"),
-						).Block(
-							jen.Id("hdr").Dot(originalMethodName).Call(paramIn...),
-						),
-					),
+						jen.List(jen.Id("_"), jen.Id("ent")).Op(":=").Range().Id("h").Dot("merged").Call().Dot(newFieldName),
+					).Block(body...),
 				)
 		}
 	}
 
 	var fields []jen.Code
-	var clearFields []jen.Code
+	merged := jen.Dict{}
 	for _, method := range ifaceType.Methods.List {
 		for _, methodName := range method.Names {
 			originalMethodName := methodName.Name
-			newInterfaceName := strings.TrimPrefix(originalMethodName, "Handle") + "Handler"
-			fields = append(fields, jen.Id("_"+newInterfaceName).Id("[]"+newInterfaceName))
-			clearFields = append(clearFields, jen.Id("h").Dot("_"+newInterfaceName).Op("=").Nil())
+			newInterfaceName := tg.prefix + strings.TrimPrefix(originalMethodName, "Handle") + "Handler"
+			newFieldName := "_" + newInterfaceName
+			fields = append(fields, jen.Id(newFieldName).Id("[]*entry["+newInterfaceName+"]"))
+			merged[jen.Id(newFieldName)] = jen.Id("merge").Call(jen.Id("parent").Dot(newFieldName), jen.Id("own").Dot(newFieldName))
 		}
 	}
-	f.Type().Id("MultipleHandler").Struct(fields...)
+	f.Type().Id(tableName).Struct(fields...)
+	f.Type().Id(tg.name).Struct(jen.Id("handlers[" + tableName + "]"))
+
+	f.Comment("merged returns the handlers registered to h merged with those of its parent, sorted by priority.")
+	f.Func().
+		Params(jen.Id("h").Id("*" + tg.name)).Id("merged").
+		Params().Id("*" + tableName).
+		Block(jen.Return(jen.Id("h").Dot("mergedWith").Call(
+			jen.Func().Params(jen.List(jen.Id("parent"), jen.Id("own")).Id("*" + tableName)).Id("*" + tableName).Block(
+				jen.Return(jen.Op("&").Id(tableName).Values(merged)),
+			),
+		)))
 
 	blocks := []jen.Code{
-		jen.Id("reg").Op(":=").False(),
-		jen.Var().Id("funcs").Id("[]func()"),
+		jen.Id("r").Op(":=").Id("newRegistration").Call(jen.Id("opts")),
+		jen.Var().List(jen.Id("add"), jen.Id("del")).Id("[]func(*" + tableName + ")"),
 	}
 	for _, method := range ifaceType.Methods.List {
 		for _, methodName := range method.Names {
 			originalMethodName := methodName.Name
-			newInterfaceName := strings.TrimPrefix(originalMethodName, "Handle") + "Handler"
+			newInterfaceName := tg.prefix + strings.TrimPrefix(originalMethodName, "Handle") + "Handler"
 			newFieldName := "_" + newInterfaceName
 			blocks = append(blocks, jen.If(
 				jen.List(jen.Id("hdr"), jen.Id("ok")).Op(":=").Op("hdr").Assert(jen.Id(newInterfaceName)),
 				jen.Id("ok"),
 			).Block(
-				jen.Id("h").Dot(newFieldName).Op("=").Append(jen.Id("h").Dot(newFieldName), jen.Id("hdr")),
-				jen.Id("reg").Op("=").True(),
-				jen.Id("funcs").Op("=").Append(jen.Id("funcs"),
-					jen.Func().Params().Block(
-						jen.Id("h").Dot(newFieldName).Op("=").Id("deleteVal").Call(
-							jen.Id("h").Dot(newFieldName),
-							jen.Id("hdr"),
-						),
+				jen.Id("e").Op(":=").Op("&").Id("entry["+newInterfaceName+"]").Values(
+					jen.Id("registration").Op(":").Id("r"),
+					jen.Id("hdr").Op(":").Id("hdr"),
+				),
+				jen.Id("add").Op("=").Append(jen.Id("add"),
+					jen.Func().Params(jen.Id("t").Id("*"+tableName)).Block(
+						jen.Id("t").Dot(newFieldName).Op("=").Id("insert").Call(jen.Id("t").Dot(newFieldName), jen.Id("e")),
+					),
+				),
+				jen.Id("del").Op("=").Append(jen.Id("del"),
+					jen.Func().Params(jen.Id("t").Id("*"+tableName)).Block(
+						jen.Id("t").Dot(newFieldName).Op("=").Id("deleteVal").Call(jen.Id("t").Dot(newFieldName), jen.Id("e")),
 					),
 				),
 			))
 		}
 	}
 	blocks = append(blocks,
-		jen.If(jen.Id("!reg")).
+		jen.If(jen.Len(jen.Id("add")).Op("==").Lit(0)).
 			Block(
 				jen.Panic(jen.Lit("not a valid handler")),
 			),
+		jen.Id("h").Dot("update").Call(jen.Id("add").Op("...")),
 		jen.Return(jen.Func().Params().Block(
-			jen.For(
-				jen.List(jen.Id("_"), jen.Id("f")),
-			).Op(":=").Range().Id("funcs").Block(jen.Id("f").Call()),
+			jen.Id("h").Dot("update").Call(jen.Id("del").Op("...")),
 		)),
 	)
 	f.Func().
-		Params(jen.Id("h").Id("*MultipleHandler")).Id("Register").
-		Params(jen.Id("hdr").Any()).Id("func()").
+		Params(jen.Id("h").Id("*"+tg.name)).Id("Register").
+		Params(jen.Id("hdr").Any(), jen.Id("opts").Op("...").Id("RegisterOption")).Id("func()").
 		Block(blocks...)
 	f.Func().
-		Params(jen.Id("h").Id("*MultipleHandler")).Id("Clear").
+		Params(jen.Id("h").Id("*" + tg.name)).Id("Clear").
 		Params().
-		Block(clearFields...)
+		Block(jen.Id("h").Dot("clear").Call())
+
+}
 
+// signature returns the signature of the method with the name passed in the interface.
+func signature(iface *types.Interface, name string) *types.Signature {
+	for i := 0; i < iface.NumMethods(); i++ {
+		if m := iface.Method(i); m.Name() == name {
+			return m.Type().(*types.Signature)
+		}
+	}
+	panic(name)
+}
+
+// unalias resolves aliases such as player.Context in the type passed, so that the underlying type is used
+// in the generated code.
+func unalias(t types.Type) types.Type {
+	switch t := types.Unalias(t).(type) {
+	case *types.Pointer:
+		return types.NewPointer(unalias(t.Elem()))
+	case *types.Slice:
+		return types.NewSlice(unalias(t.Elem()))
+	default:
+		return t
+	}
+}
+
+func typeString(t types.Type) string {
+	return types.TypeString(unalias(t), func(p *types.Package) string {
+		return p.Name()
+	})
 }
 
-func getFuncIn(method *ast.Field, reflectionIface reflect.Type, originalMethodName string) ([]jen.Code, []jen.Code) {
+func getFuncIn(method *ast.Field, iface *types.Interface, originalMethodName string) ([]jen.Code, []jen.Code) {
 	params := method.Type.(*ast.FuncType).Params.List
+	sig := signature(iface, originalMethodName)
 	var typedIn []jen.Code
 	var paramIn []jen.Code
-	seq := 1
+	seq := 0
 	for _, param := range params {
 		var names []jen.Code
 		for _, pN := range param.Names {
 			names = append(names, jen.Id(pN.Name))
 		}
-		reflectionMethod, found := reflectionIface.MethodByName(originalMethodName)
-		if !found {
-			panic(originalMethodName)
-		}
-		paramType := reflectionMethod.Type.In(seq).String()
-		if paramType == "*event.Context[*github.com/df-mc/dragonfly/server/player.Player]" {
-			paramType = "*event.Context[*player.Player]"
-		}
+		paramType := typeString(sig.Params().At(seq).Type())
 		typedIn = append(typedIn, jen.List(names...).Id(paramType))
 		paramIn = append(paramIn, names...)
 		seq += len(names)
@@ -176,9 +292,22 @@ func getFuncIn(method *ast.Field, reflectionIface reflect.Type, originalMethodNa
 	return typedIn, paramIn
 }
 
-func getDoc(m *debug.Module) *doc.Package {
-	fset := token.NewFileSet()
-	pkg, _ := parser.ParseFile(fset, filepath.Join(build.Default.GOPATH, "/pkg/mod/github.com/df-mc/dragonfly@"+m.Version+"/server/player/handler.go"), nil, parser.ParseComments)
-	d, _ := doc.NewFromFiles(fset, []*ast.File{pkg}, filepath.Join(build.Default.GOPATH, "/pkg/mod/github.com/df-mc/dragonfly@"+m.Version+"/server/player"), doc.AllDecls)
-	return d
+// getCtxParam returns the name of the first parameter of the method if it is a cancellable event context.
+func getCtxParam(method *ast.Field, iface *types.Interface, originalMethodName string) (string, bool) {
+	params := method.Type.(*ast.FuncType).Params.List
+	if len(params) == 0 || len(params[0].Names) == 0 {
+		return "", false
+	}
+	ptr, ok := unalias(signature(iface, originalMethodName).Params().At(0).Type()).(*types.Pointer)
+	if !ok {
+		return "", false
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	if !ok {
+		return "", false
+	}
+	if obj := named.Obj(); obj.Pkg() == nil || obj.Pkg().Path() != eventPkg || obj.Name() != "Context" {
+		return "", false
+	}
+	return params[0].Names[0].Name, true
 }
@@ -1,11 +1,18 @@
 package util
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 )
 
 var WorkingPath, _ = os.Getwd()
 
+// Backups is the number of previous versions of a file that WriteFile keeps as backups, named after the file
+// with a .bak suffix, followed by a number for all but the most recent one.
+var Backups = 3
+
 func MustReadFile(path string) []byte {
 	return SelectAnyByteSlice(os.ReadFile(path))
 }
@@ -15,7 +22,99 @@ func MustDeleteFile(path string) {
 }
 
 func MustWriteFile(path string, data []byte) {
-	Must(os.WriteFile(path, data, 0666))
+	Must(WriteFile(path, data))
+}
+
+// WriteFile writes the data passed to the file at the path passed, so that the file holds either its previous
+// or its new content if the server crashes or the disk is full while writing. The data is written to a
+// temporary file in the same directory, which is synced to disk and then renamed to the path passed.
+// The previous version of the file is kept as a backup, as are the Backups - 1 versions before it.
+func WriteFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	if err := backup(path); err != nil {
+		return fmt.Errorf("back up %v: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	syncDir(filepath.Dir(path))
+	return nil
+}
+
+// syncDir syncs the directory at the path passed to disk, so that a file renamed in it is not lost in a crash.
+// Errors are ignored, as directories cannot be synced on all systems.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+}
+
+// backupName returns the name of the nth most recent backup of the file at the path passed, starting at 1.
+func backupName(path string, n int) string {
+	if n == 1 {
+		return path + ".bak"
+	}
+	return fmt.Sprintf("%v.bak.%v", path, n)
+}
+
+// backup rotates the backups of the file at the path passed and copies the file to the most recent backup.
+func backup(path string) error {
+	if Backups <= 0 || !FileExist(path) {
+		return nil
+	}
+	for n := Backups - 1; n >= 1; n-- {
+		if FileExist(backupName(path, n)) {
+			if err := os.Rename(backupName(path, n), backupName(path, n+1)); err != nil {
+				return err
+			}
+		}
+	}
+	return copyFile(path, backupName(path, 1))
+}
+
+// copyFile copies the file at the path src to the path dst and syncs the copy to disk.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
 }
 
 func FileExist(path string) bool {
@@ -0,0 +1,68 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ops.txt")
+	for i := 1; i <= 5; i++ {
+		if err := WriteFile(path, []byte(fmt.Sprint("version ", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, want := range map[string]string{
+		path:            "version 5",
+		path + ".bak":   "version 4",
+		path + ".bak.2": "version 3",
+		path + ".bak.3": "version 2",
+	} {
+		if data, err := os.ReadFile(name); err != nil || string(data) != want {
+			t.Errorf("expected %v to hold %q, got %q, %v", filepath.Base(name), want, data, err)
+		}
+	}
+	if FileExist(path + ".bak.4") {
+		t.Error("expected no more than Backups backups to be kept")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Errorf("expected the temporary files to be removed, got %v files", len(entries))
+	}
+}
+
+func TestWriteFileMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("expected the mode of the file to be kept, got %v, %v", info.Mode(), err)
+	}
+}
+
+func TestWriteFileNoBackups(t *testing.T) {
+	defer func(n int) {
+		Backups = n
+	}(Backups)
+	Backups = 0
+
+	path := filepath.Join(t.TempDir(), "ops.txt")
+	for i := 0; i < 2; i++ {
+		if err := WriteFile(path, []byte("a")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if FileExist(backupName(path, 1)) {
+		t.Fatal("expected no backup to be kept")
+	}
+}
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// watchDelay is the time waited after a change to a watched file before it is reported, so that a burst of
+// changes, such as those made by an editor saving a file, is reported once.
+const watchDelay = 100 * time.Millisecond
+
+// WatchFile calls f every time the file at the path passed is created, changed, replaced or removed, until the
+// function returned is called. Changes are detected using inotify on Linux. On other systems, or if inotify
+// cannot be used, the modification time and size of the file are polled at the interval passed instead.
+// f is called from a separate goroutine. It is also called for changes made by the program itself, such as
+// writes using WriteFile, which renames a temporary file to the path. Callers that write the file should
+// compare its content to what they last wrote to ignore those.
+func WatchFile(path string, interval time.Duration, f func()) (stop func()) {
+	done := make(chan struct{})
+	var (
+		mu    sync.Mutex
+		timer *time.Timer
+	)
+	changed := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(watchDelay, func() {
+			select {
+			case <-done:
+			default:
+				f()
+			}
+		})
+	}
+	if err := watchNotify(path, changed, done); err != nil {
+		pollFile(path, interval, changed, done)
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+		})
+	}
+}
+
+// pollFile calls changed every time the modification time or size of the file at the path passed changed,
+// or the file was created or removed, until done is closed. The file is polled from a separate goroutine,
+// comparing it to its state when pollFile was called.
+func pollFile(path string, interval time.Duration, changed func(), done <-chan struct{}) {
+	stat := func() (time.Time, int64, bool) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, 0, false
+		}
+		return info.ModTime(), info.Size(), true
+	}
+	modTime, size, exists := stat()
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-t.C:
+				m, s, e := stat()
+				if !m.Equal(modTime) || s != size || e != exists {
+					modTime, size, exists = m, s, e
+					changed()
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,53 @@
+package util
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+// watchNotify watches the file at the path passed using inotify and calls changed every time it is changed,
+// until done is closed. The directory of the file is watched, so that the file being created, removed or
+// replaced by renaming another file to it is detected too.
+func watchNotify(path string, changed func(), done <-chan struct{}) error {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return err
+	}
+	mask := uint32(syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY | syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM)
+	if _, err := syscall.InotifyAddWatch(fd, filepath.Dir(path), mask); err != nil {
+		_ = syscall.Close(fd)
+		return err
+	}
+	// The file descriptor is non-blocking, so reads use the runtime poller and are interrupted by closing it.
+	f := os.NewFile(uintptr(fd), "inotify")
+	name := []byte(filepath.Base(path))
+	go func() {
+		<-done
+		_ = f.Close()
+	}()
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, err := f.Read(buf)
+			if err != nil {
+				return
+			}
+			for off := 0; off+syscall.SizeofInotifyEvent <= n; {
+				ev := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[off]))
+				end := off + syscall.SizeofInotifyEvent + int(ev.Len)
+				if end > n {
+					break
+				}
+				evName := bytes.TrimRight(buf[off+syscall.SizeofInotifyEvent:end], "\x00")
+				if bytes.Equal(evName, name) {
+					changed()
+				}
+				off = end
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package util
+
+import (
+	"errors"
+)
+
+// watchNotify returns an error, as notifications of file changes are only supported on Linux, so that the file
+// is polled instead.
+func watchNotify(string, func(), <-chan struct{}) error {
+	return errors.New("file notifications are not supported on this system")
+}
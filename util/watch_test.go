@@ -0,0 +1,87 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// watchCalls returns a function counting its calls in n and sending to the channel returned for every call.
+func watchCalls(n *atomic.Int32) (func(), <-chan struct{}) {
+	c := make(chan struct{}, 16)
+	return func() {
+		n.Add(1)
+		c <- struct{}{}
+	}, c
+}
+
+// await waits for a value to be sent to the channel passed, failing the test if none is sent in time.
+func await(t *testing.T, c <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-c:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected %v to be reported", what)
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	var n atomic.Int32
+	f, calls := watchCalls(&n)
+	stop := WatchFile(path, 10*time.Millisecond, f)
+
+	if err := os.WriteFile(path, []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	await(t, calls, "creating the file")
+	// A burst of changes is reported once.
+	for i := 0; i < 10; i++ {
+		if err := os.WriteFile(path, []byte(fmt.Sprint(i)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	await(t, calls, "changing the file")
+	if err := WriteFile(path, []byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	await(t, calls, "replacing the file")
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	await(t, calls, "removing the file")
+
+	stop()
+	stop()
+	if got := n.Load(); got != 4 {
+		t.Fatalf("expected 4 calls, got %v", got)
+	}
+}
+
+func TestPollFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	var n atomic.Int32
+	changed, calls := watchCalls(&n)
+	done := make(chan struct{})
+	defer close(done)
+	pollFile(path, time.Millisecond, changed, done)
+
+	if err := os.WriteFile(path, []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	await(t, calls, "creating the file")
+	if err := os.WriteFile(path, []byte("ab"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	await(t, calls, "changing the file")
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	await(t, calls, "removing the file")
+	if got := n.Load(); got != 3 {
+		t.Fatalf("expected 3 calls, got %v", got)
+	}
+}